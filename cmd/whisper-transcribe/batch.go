@@ -0,0 +1,240 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/cyber/whisper-transcribe/internal/config"
+	"github.com/cyber/whisper-transcribe/internal/downloader"
+	"github.com/cyber/whisper-transcribe/internal/transcriber"
+	"github.com/spf13/cobra"
+)
+
+var (
+	batchList  string
+	batchURLs  []string
+	batchFiles []string
+)
+
+func newBatchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "batch [sources...]",
+		Short: "Transcribe many URLs or files from a list",
+		Long: `Transcribe several sources in one run. Sources can be given as
+arguments, with repeated --url and --file flags, or in a list file with
+one URL or file path per line (use "-" to read the list from stdin).
+Blank lines and lines starting with # are ignored.
+
+Downloads run concurrently and up to --workers sources are transcribed
+at the same time. A failure does not stop the batch; a summary is
+printed at the end and the exit status is non-zero if any source failed.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE:          runBatch,
+	}
+
+	cmd.Flags().StringVarP(&batchList, "list", "l", "", `file with one source per line ("-" for stdin)`)
+	cmd.Flags().StringArrayVarP(&batchURLs, "url", "u", nil, "YouTube URL to transcribe (repeatable)")
+	cmd.Flags().StringArrayVarP(&batchFiles, "file", "f", nil, "local audio file to transcribe (repeatable)")
+
+	return cmd
+}
+
+func runBatch(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	resolveModel(cfg)
+	if err := newJob(cfg).Validate(); err != nil {
+		return err
+	}
+
+	sources := append([]string{}, args...)
+	if batchList != "" {
+		listed, err := readSourceList(batchList)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, listed...)
+	}
+
+	// Sources are validated up front; invalid ones are reported as failures
+	// in the summary without stopping the batch.
+	var items []batchItem
+	for _, source := range sources {
+		items = append(items, newBatchItem(cfg, source, isURL(source)))
+	}
+	for _, u := range batchURLs {
+		items = append(items, newBatchItem(cfg, u, true))
+	}
+	for _, f := range batchFiles {
+		items = append(items, newBatchItem(cfg, f, false))
+	}
+
+	if len(items) == 0 {
+		return fmt.Errorf("no sources given (use arguments, --list, --url or --file)")
+	}
+
+	runnable := 0
+	for _, item := range items {
+		if item.err == nil {
+			runnable++
+		}
+	}
+
+	if runnable > 0 {
+		if batchList == "-" {
+			// Stdin holds the source list, so the download prompt can't be used.
+			if err := transcriber.CheckModel(cfg.DefaultModel, newJob(cfg).EnglishOnlyFits()); err != nil {
+				return fmt.Errorf("%w (download it first when reading sources from stdin)", err)
+			}
+		} else if err := ensureModel(cfg); err != nil {
+			return err
+		}
+	}
+
+	// Playlists are expanded first so that all videos share one queue.
+	var jobs []queuedJob
+	spans := make([][2]int, len(items))
+	playlists := make([]*playlistRun, len(items))
+	for i, item := range items {
+		prefix := fmt.Sprintf("[%d/%d] ", i+1, len(items))
+		start := len(jobs)
+		switch {
+		case item.err != nil:
+		case item.playlist:
+			run, err := preparePlaylist(cfg, item.source, prefix)
+			if err != nil {
+				fmt.Printf("%sFailed: %v\n\n", prefix, err)
+				items[i].err = err
+				break
+			}
+			playlists[i] = run
+			jobs = append(jobs, run.jobs...)
+		default:
+			jobs = append(jobs, queuedJob{job: item.job, prefix: prefix})
+		}
+		spans[i] = [2]int{start, len(jobs)}
+	}
+
+	jobResults := runJobs(cfg, jobs)
+	fmt.Println()
+
+	var results []jobResult
+	for i, item := range items {
+		if item.err != nil {
+			results = append(results, jobResult{Source: item.source, Err: item.err})
+			continue
+		}
+		itemResults := jobResults[spans[i][0]:spans[i][1]]
+		results = append(results, itemResults...)
+		if run := playlists[i]; run != nil {
+			if err := run.writeIndex(itemResults); err != nil {
+				results = append(results, jobResult{Source: item.source, Err: err})
+			}
+		}
+	}
+
+	failed := printBatchSummary(os.Stdout, results)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d sources failed", failed, len(results))
+	}
+	return nil
+}
+
+// batchItem is one source of a batch: a single job, or a playlist that is
+// expanded before the batch starts.
+type batchItem struct {
+	source   string
+	job      *config.TranscriptionConfig
+	playlist bool
+	err      error
+}
+
+func newBatchItem(cfg *config.Config, source string, isURL bool) batchItem {
+	item := batchItem{source: source}
+	switch {
+	case isURL && downloader.IsPlaylistURL(source):
+		item.playlist = true
+	case isURL:
+		item.job, item.err = newURLJob(cfg, source)
+	default:
+		item.job, item.err = newFileJob(cfg, source)
+	}
+	return item
+}
+
+func isURL(source string) bool {
+	source = strings.ToLower(strings.TrimSpace(source))
+	return strings.HasPrefix(source, "http://") ||
+		strings.HasPrefix(source, "https://") ||
+		strings.HasPrefix(source, "youtu.be/") ||
+		strings.Contains(source, "youtube.com/")
+}
+
+// readSourceList reads one source per line from path, or stdin for "-".
+func readSourceList(path string) ([]string, error) {
+	var r io.Reader
+	if path == "-" {
+		r = os.Stdin
+	} else {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("open list: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var sources []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		sources = append(sources, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read list: %w", err)
+	}
+	return sources, nil
+}
+
+// printBatchSummary writes a table of job results and returns the number
+// of failures.
+func printBatchSummary(w io.Writer, results []jobResult) int {
+	failed := 0
+	totalWords := 0
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STATUS\tSOURCE\tWORDS\tOUTPUT")
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			fmt.Fprintf(tw, "FAILED\t%s\t-\t%v\n", r.Source, r.Err)
+			continue
+		}
+		totalWords += r.WordCount
+		output := "-"
+		if len(r.OutputPaths) > 0 {
+			output = r.OutputPaths[0]
+			for _, p := range r.OutputPaths[1:] {
+				output += ", " + filepath.Base(p)
+			}
+		}
+		fmt.Fprintf(tw, "OK\t%s\t%d\t%s\n", r.Source, r.WordCount, output)
+	}
+	tw.Flush()
+
+	fmt.Fprintf(w, "\n%d succeeded, %d failed, %d words total\n",
+		len(results)-failed, failed, totalWords)
+	return failed
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/cyber/whisper-transcribe/internal/models"
+	"github.com/cyber/whisper-transcribe/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	cleanupDryRun bool
+	cleanupGrace  time.Duration
+)
+
+func newCleanupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cleanup",
+		Short: "Remove temporary audio left behind by crashed or killed jobs",
+		Long: `Every job downloads and converts audio in its own workspace under
+the system temp directory and removes it when it ends. Workspaces of
+jobs that crashed or were killed, and temporary files of earlier
+versions, are left behind; this command removes them. Workspaces of
+running jobs are never touched.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runCleanup,
+	}
+	cmd.Flags().BoolVarP(&cleanupDryRun, "dry-run", "n", false, "list what would be removed without removing it")
+	cmd.Flags().DurationVar(&cleanupGrace, "older-than", time.Hour, "only remove files of earlier versions not modified for this long")
+	return cmd
+}
+
+func runCleanup(cmd *cobra.Command, args []string) error {
+	stale, err := workspace.FindStale(cleanupGrace)
+	if err != nil {
+		return fmt.Errorf("find stale workspaces: %w", err)
+	}
+	return cleanup(os.Stdout, stale, cleanupDryRun)
+}
+
+// cleanup removes the stale entries, or only lists them on a dry run.
+func cleanup(w io.Writer, stale []workspace.Stale, dryRun bool) error {
+	if len(stale) == 0 {
+		fmt.Fprintln(w, "Nothing to clean up")
+		return nil
+	}
+
+	var freed int64
+	var failed int
+	for _, s := range stale {
+		if dryRun {
+			fmt.Fprintf(w, "Would remove %s (%s)\n", s.Path, models.FormatBytes(s.Size))
+			freed += s.Size
+			continue
+		}
+		if err := os.RemoveAll(s.Path); err != nil {
+			fmt.Fprintf(w, "Failed to remove %s: %v\n", s.Path, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(w, "Removed %s (%s)\n", s.Path, models.FormatBytes(s.Size))
+		freed += s.Size
+	}
+
+	if dryRun {
+		fmt.Fprintf(w, "%s would be freed\n", models.FormatBytes(freed))
+		return nil
+	}
+	fmt.Fprintf(w, "Freed %s\n", models.FormatBytes(freed))
+	if failed > 0 {
+		return fmt.Errorf("%d of %d entries could not be removed", failed, len(stale))
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/cyber/whisper-transcribe/internal/formatter"
+	"github.com/cyber/whisper-transcribe/internal/history"
+	"github.com/spf13/cobra"
+)
+
+var historyLimit int
+
+func newHistoryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "List, search and re-open past transcriptions",
+		Long: `Every completed transcription is recorded in a history file
+(~/.local/share/whisper-transcribe/history.json by default, or
+history_file in the config). Without a subcommand the most recent
+entries are listed.`,
+		Args: cobra.NoArgs,
+		RunE: runHistoryList,
+	}
+	cmd.PersistentFlags().IntVarP(&historyLimit, "limit", "n", 20, "maximum number of entries to list (0 for all)")
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List recent transcriptions",
+		Args:  cobra.NoArgs,
+		RunE:  runHistoryList,
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "search <query>",
+		Short: "Find transcriptions by title, channel, source or model",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  runHistorySearch,
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "show <id>",
+		Short: "Show the details of a transcription",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runHistoryShow,
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "open <id>",
+		Short: "Open a transcript in $EDITOR",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runHistoryOpen,
+	})
+
+	return cmd
+}
+
+func openHistory() (*history.Store, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	return history.Open(cfg.HistoryFile), nil
+}
+
+func runHistoryList(cmd *cobra.Command, args []string) error {
+	store, err := openHistory()
+	if err != nil {
+		return err
+	}
+	entries, err := store.List()
+	if err != nil {
+		return err
+	}
+	printHistory(os.Stdout, entries, historyLimit)
+	return nil
+}
+
+func runHistorySearch(cmd *cobra.Command, args []string) error {
+	store, err := openHistory()
+	if err != nil {
+		return err
+	}
+	entries, err := store.Search(strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+	printHistory(os.Stdout, entries, historyLimit)
+	return nil
+}
+
+func runHistoryShow(cmd *cobra.Command, args []string) error {
+	entry, err := historyEntry(args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("ID:        %d\n", entry.ID)
+	fmt.Printf("Title:     %s\n", entry.Title())
+	if entry.Metadata.Channel != "" {
+		fmt.Printf("Channel:   %s\n", entry.Metadata.Channel)
+	}
+	fmt.Printf("Source:    %s\n", entry.Source)
+	fmt.Printf("Duration:  %s\n", entry.Metadata.Duration)
+	fmt.Printf("Model:     %s\n", entry.Model)
+	fmt.Printf("Words:     %d\n", entry.WordCount)
+	fmt.Printf("Started:   %s\n", entry.StartedAt.Local().Format("2006-01-02 15:04:05"))
+	fmt.Printf("Completed: %s\n", entry.CompletedAt.Local().Format("2006-01-02 15:04:05"))
+	for _, path := range entry.OutputPaths {
+		fmt.Printf("Output:    %s\n", path)
+	}
+	return nil
+}
+
+func runHistoryOpen(cmd *cobra.Command, args []string) error {
+	entry, err := historyEntry(args[0])
+	if err != nil {
+		return err
+	}
+
+	path := formatter.PrimaryOutput(entry.OutputPaths)
+	if path == "" {
+		return fmt.Errorf("entry %d has no output files", entry.ID)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("transcript no longer available: %w", err)
+	}
+
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vim"
+	}
+	c := exec.Command(editor, path)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	return c.Run()
+}
+
+func historyEntry(arg string) (*history.Entry, error) {
+	id, err := strconv.Atoi(arg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid history ID %q", arg)
+	}
+	store, err := openHistory()
+	if err != nil {
+		return nil, err
+	}
+	return store.Get(id)
+}
+
+// printHistory writes a table of up to limit entries; zero means all.
+func printHistory(w io.Writer, entries []history.Entry, limit int) {
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "No transcriptions found.")
+		return
+	}
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tDATE\tMODEL\tWORDS\tTITLE")
+	for _, e := range entries {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%d\t%s\n",
+			e.ID, e.CompletedAt.Local().Format("2006-01-02 15:04"), e.Model, e.WordCount, e.Title())
+	}
+	tw.Flush()
+}
@@ -2,18 +2,26 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/spf13/cobra"
+	"github.com/cyber/whisper-transcribe/internal/cache"
 	"github.com/cyber/whisper-transcribe/internal/config"
 	"github.com/cyber/whisper-transcribe/internal/downloader"
+	"github.com/cyber/whisper-transcribe/internal/formatter"
+	"github.com/cyber/whisper-transcribe/internal/history"
 	"github.com/cyber/whisper-transcribe/internal/models"
 	"github.com/cyber/whisper-transcribe/internal/pipeline"
+	"github.com/cyber/whisper-transcribe/internal/queue"
 	"github.com/cyber/whisper-transcribe/internal/transcriber"
 	"github.com/cyber/whisper-transcribe/internal/tui"
+	"github.com/spf13/cobra"
 )
 
 var (
@@ -24,9 +32,51 @@ var (
 	model      string
 	timestamps bool
 	outputDir  string
+	formats    []string
+
+	playlistItems string
+	dateAfter     string
+	maxVideos     int
+
+	workers   int
+	downloads int
+	force     bool
+
+	language string
+	task     string
+	profile  string
+
+	vocabulary string
+	diarize    string
+	rttm       string
+	captions   string
+
+	normalize   bool
+	denoise     bool
+	trimSilence bool
+
+	chunkLength  time.Duration
+	chunkWorkers int
+
+	start, end         string
+	clipStart, clipEnd time.Duration
+
+	keepAudio bool
 )
 
+// rootCmd holds the persistent flags shared by all subcommands.
+var rootCmd *cobra.Command
+
 func main() {
+	rootCmd = newRootCmd()
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// newRootCmd builds the root command with its flags and subcommands.
+func newRootCmd() *cobra.Command {
 	rootCmd := &cobra.Command{
 		Use:   "whisper-transcribe",
 		Short: "Transcribe YouTube videos to Markdown using Whisper",
@@ -37,23 +87,77 @@ using local OpenAI Whisper (whisper.cpp) transcription.`,
 	}
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file path")
+	rootCmd.PersistentFlags().StringVarP(&model, "model", "m", "", "Whisper model (see \"models list\"), or auto to pick one for this machine")
+	rootCmd.PersistentFlags().BoolVarP(&timestamps, "timestamps", "t", false, "include timestamps in output")
+	rootCmd.PersistentFlags().StringVarP(&outputDir, "output", "o", "", "output directory")
+	rootCmd.PersistentFlags().StringSliceVar(&formats, "format", nil, "output formats ("+strings.Join(formatter.Names(), ", ")+")")
+	rootCmd.PersistentFlags().StringVar(&playlistItems, "playlist-items", "", "playlist items to transcribe, e.g. 1-10,15")
+	rootCmd.PersistentFlags().StringVar(&dateAfter, "date-after", "", "only transcribe playlist videos uploaded on or after YYYYMMDD")
+	rootCmd.PersistentFlags().IntVar(&maxVideos, "max-videos", 0, "maximum number of playlist videos to transcribe")
+	rootCmd.PersistentFlags().IntVar(&workers, "workers", 0, "number of concurrent whisper runs")
+	rootCmd.PersistentFlags().IntVar(&downloads, "downloads", 0, "number of concurrent downloads")
+	rootCmd.PersistentFlags().BoolVar(&force, "force", false, "re-transcribe even if a cached transcription exists")
+	rootCmd.PersistentFlags().StringVar(&language, "language", "", "spoken language code (en, de, es, ...) or auto to detect it")
+	rootCmd.PersistentFlags().StringVar(&task, "task", "", "transcribe, or translate to English")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "whisper decoding profile (default, fast, accurate, podcast, or one from the config)")
+	rootCmd.PersistentFlags().StringVar(&vocabulary, "vocabulary", "", "YAML file of domain terms and misspelling fixes")
+	rootCmd.PersistentFlags().StringVar(&diarize, "diarize", "", "label speakers: stereo (one speaker per channel) or tdrz (tinydiarize model; turns alternate between two labels)")
+	rootCmd.PersistentFlags().StringVar(&captions, "captions", "", "YouTube captions: whisper (ignore them), prefer (skip whisper when they exist) or both (report whisper's word error rate)")
+	rootCmd.PersistentFlags().BoolVar(&normalize, "normalize", false, "normalize loudness before transcribing")
+	rootCmd.PersistentFlags().BoolVar(&denoise, "denoise", false, "filter out rumble and background noise before transcribing")
+	rootCmd.PersistentFlags().BoolVar(&trimSilence, "trim-silence", false, "skip leading and trailing silence")
+	rootCmd.PersistentFlags().DurationVar(&chunkLength, "chunk-length", 0, "split longer audio at pauses into chunks of at most this length, e.g. 10m, and transcribe them in parallel")
+	rootCmd.PersistentFlags().IntVar(&chunkWorkers, "chunk-workers", 0, "number of chunks of a job transcribed at once")
+	rootCmd.PersistentFlags().BoolVar(&keepAudio, "keep-audio", false, "save downloaded audio as a WAV next to the transcript")
+	rootCmd.Flags().StringVar(&start, "start", "", "transcribe from this time, e.g. 1:30:00 or 5400")
+	rootCmd.Flags().StringVar(&end, "end", "", "transcribe up to this time, e.g. 1:40:00")
+	rootCmd.Flags().StringVar(&rttm, "rttm", "", "RTTM file with speaker turns from an external diarization tool")
 	rootCmd.Flags().BoolVar(&noTUI, "no-tui", false, "run in CLI mode without TUI")
 	rootCmd.Flags().StringVarP(&url, "url", "u", "", "YouTube URL to transcribe")
 	rootCmd.Flags().StringVarP(&localFile, "file", "f", "", "local audio file to transcribe")
-	rootCmd.Flags().StringVarP(&model, "model", "m", "", "Whisper model (tiny, base, small, medium, large)")
-	rootCmd.Flags().BoolVarP(&timestamps, "timestamps", "t", false, "include timestamps in output")
-	rootCmd.Flags().StringVarP(&outputDir, "output", "o", "", "output directory")
 
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
-	}
+	rootCmd.AddCommand(newBatchCmd())
+	rootCmd.AddCommand(newHistoryCmd())
+	rootCmd.AddCommand(newModelsCmd())
+	rootCmd.AddCommand(newCleanupCmd())
+
+	return rootCmd
 }
 
 func run(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	if noTUI || url != "" || localFile != "" {
+		return runCLI(cfg, url, localFile)
+	}
+
+	return runTUI(cfg)
+}
+
+// loadConfig reads the config file and applies the persistent flag overrides.
+func loadConfig() (*config.Config, error) {
 	cfg, err := config.Load(cfgFile)
 	if err != nil {
-		return fmt.Errorf("load config: %w", err)
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+	if err := downloader.ValidateDate(dateAfter); err != nil {
+		return nil, err
+	}
+
+	models.SetBaseURL(cfg.ModelMirror)
+	registry := cfg.ModelRegistry
+	if env := os.Getenv("WHISPER_MODEL_REGISTRY"); env != "" {
+		registry = env
+	}
+	if registry != "" {
+		if err := models.LoadRegistry(registry); err != nil {
+			return nil, err
+		}
+	} else if err := models.LoadDefaultRegistry(); err != nil {
+		return nil, err
 	}
 
 	if model != "" {
@@ -62,15 +166,86 @@ func run(cmd *cobra.Command, args []string) error {
 	if outputDir != "" {
 		cfg.OutputDir = outputDir
 	}
-	if timestamps {
-		cfg.Timestamps = true
+	// Flags that were given override the config even when zero or false.
+	flags := rootCmd.PersistentFlags()
+	if flags.Changed("timestamps") {
+		cfg.Timestamps = timestamps
 	}
-
-	if noTUI || url != "" || localFile != "" {
-		return runCLI(cfg, url, localFile)
+	if len(formats) > 0 {
+		cfg.OutputFormats = formats
+	}
+	if flags.Changed("workers") {
+		cfg.Workers = workers
+	}
+	if flags.Changed("downloads") {
+		cfg.MaxDownloads = downloads
+	}
+	if language != "" {
+		cfg.Language = strings.ToLower(language)
+	}
+	if task != "" {
+		cfg.Task = strings.ToLower(task)
+	}
+	if profile != "" {
+		cfg.Profile = strings.ToLower(profile)
+	}
+	// Checked here so that newJob can rely on the profile existing.
+	if _, err := cfg.WhisperProfile(""); err != nil {
+		return nil, err
+	}
+	if vocabulary != "" {
+		cfg.Vocabulary = vocabulary
+	}
+	if diarize != "" {
+		cfg.Diarize = strings.ToLower(diarize)
+	}
+	if captions != "" {
+		cfg.Captions = strings.ToLower(captions)
+	}
+	if flags.Changed("normalize") {
+		cfg.Audio.Normalize = normalize
+	}
+	if flags.Changed("denoise") {
+		cfg.Audio.Denoise = denoise
+	}
+	if flags.Changed("trim-silence") {
+		cfg.Audio.TrimSilence = trimSilence
+	}
+	if flags.Changed("chunk-length") {
+		cfg.ChunkLength = chunkLength
+	}
+	if flags.Changed("chunk-workers") {
+		cfg.ChunkWorkers = chunkWorkers
+	}
+	if flags.Changed("keep-audio") {
+		cfg.KeepAudio = keepAudio
+	}
+	if clipStart, err = config.ParseClipTime(start); err != nil {
+		return nil, fmt.Errorf("--start: %w", err)
+	}
+	if clipEnd, err = config.ParseClipTime(end); err != nil {
+		return nil, fmt.Errorf("--end: %w", err)
+	}
+	if rttm != "" {
+		if _, err := transcriber.LoadRTTM(rttm); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Vocabulary != "" {
+		// Fail now rather than once per job.
+		if _, err := formatter.LoadVocabulary(cfg.Vocabulary); err != nil {
+			return nil, err
+		}
+	}
+	for i, name := range cfg.OutputFormats {
+		w, err := formatter.Lookup(name)
+		if err != nil {
+			return nil, err
+		}
+		cfg.OutputFormats[i] = w.Name()
 	}
 
-	return runTUI(cfg)
+	return cfg, nil
 }
 
 func runTUI(cfg *config.Config) error {
@@ -79,6 +254,7 @@ func runTUI(cfg *config.Config) error {
 	m.SetProgram(p)
 
 	_, err := p.Run()
+	m.Shutdown()
 	return err
 }
 
@@ -91,61 +267,227 @@ func runCLI(cfg *config.Config, videoURL, filePath string) error {
 		return fmt.Errorf("cannot specify both --url and --file")
 	}
 
-	transcriptionCfg := &config.TranscriptionConfig{
-		Model:      cfg.DefaultModel,
-		Timestamps: cfg.Timestamps,
-		OutputDir:  cfg.OutputDir,
+	resolveModel(cfg)
+	if err := newJob(cfg).Validate(); err != nil {
+		return err
 	}
 
-	if videoURL != "" {
-		if err := downloader.ValidateURL(videoURL); err != nil {
+	if videoURL != "" && downloader.IsPlaylistURL(videoURL) {
+		if clipStart > 0 || clipEnd > 0 {
+			return fmt.Errorf("--start and --end apply to single videos, not playlists")
+		}
+		if rttm != "" {
+			// The turns are those of one recording.
+			return fmt.Errorf("--rttm applies to single videos, not playlists")
+		}
+		if err := ensureModel(cfg); err != nil {
 			return err
 		}
-		transcriptionCfg.URL = videoURL
-	} else {
-		if err := validateLocalFile(filePath); err != nil {
+		results, err := runPlaylist(cfg, videoURL)
+		if err != nil {
 			return err
 		}
-		transcriptionCfg.LocalFile = filePath
+		if failed := printBatchSummary(os.Stdout, results); failed > 0 {
+			return fmt.Errorf("%d of %d videos failed", failed, len(results))
+		}
+		return nil
+	}
+
+	var transcriptionCfg *config.TranscriptionConfig
+	var err error
+	if videoURL != "" {
+		transcriptionCfg, err = newURLJob(cfg, videoURL)
+	} else {
+		transcriptionCfg, err = newFileJob(cfg, filePath)
+	}
+	if err != nil {
+		return err
 	}
 
-	// Check if model exists
-	if err := transcriber.CheckModel(cfg.DefaultModel); err != nil {
+	if err := ensureModel(cfg); err != nil {
+		return err
+	}
+
+	result := runJobs(cfg, []queuedJob{{job: transcriptionCfg}})[0]
+	if result.Err != nil {
+		return result.Err
+	}
+
+	fmt.Printf("\nTranscription complete!\n")
+	for _, path := range result.OutputPaths {
+		fmt.Printf("Output: %s\n", path)
+	}
+	fmt.Printf("Words: %d\n", result.WordCount)
+
+	return nil
+}
+
+// newURLJob creates a transcription job for a single YouTube video URL.
+func newURLJob(cfg *config.Config, videoURL string) (*config.TranscriptionConfig, error) {
+	if err := downloader.ValidateURL(videoURL); err != nil {
+		return nil, err
+	}
+	if downloader.IsPlaylistURL(videoURL) {
+		return nil, fmt.Errorf("expected a video URL, got a playlist or channel")
+	}
+	job := newJob(cfg)
+	job.URL = strings.TrimSpace(videoURL)
+	return job, nil
+}
+
+// newFileJob creates a transcription job for a local audio file.
+func newFileJob(cfg *config.Config, filePath string) (*config.TranscriptionConfig, error) {
+	if err := validateLocalFile(filePath); err != nil {
+		return nil, err
+	}
+	job := newJob(cfg)
+	job.LocalFile = strings.TrimSpace(filePath)
+	return job, nil
+}
+
+func newJob(cfg *config.Config) *config.TranscriptionConfig {
+	whisper, _ := cfg.WhisperProfile("")
+	return &config.TranscriptionConfig{
+		Model:         cfg.DefaultModel,
+		Timestamps:    cfg.Timestamps,
+		OutputDir:     cfg.OutputDir,
+		OutputFormats: cfg.OutputFormats,
+		Force:         force,
+		Language:      cfg.Language,
+		Task:          cfg.Task,
+		Whisper:       whisper,
+		Vocabulary:    cfg.Vocabulary,
+		Diarize:       cfg.Diarize,
+		RTTM:          rttm,
+		Speakers:      cfg.Speakers,
+		Captions:      cfg.Captions,
+		Audio:         cfg.Audio,
+		ChunkLength:   cfg.ChunkLength,
+		ChunkWorkers:  cfg.ChunkWorkers,
+		Start:         clipStart,
+		End:           clipEnd,
+		KeepAudio:     cfg.KeepAudio,
+	}
+}
+
+// resolveModel replaces the auto model with the best fit for this machine.
+func resolveModel(cfg *config.Config) {
+	if cfg.DefaultModel != models.Auto {
+		return
+	}
+	name, reason := models.SelectAuto(models.DetectResources(), cfg.AutoTargetRTF)
+	fmt.Printf("Auto-selected model %s (%s)\n", name, reason)
+	cfg.DefaultModel = name
+}
+
+// ensureModel checks the configured model is installed, offering to
+// download it if not.
+func ensureModel(cfg *config.Config) error {
+	if err := transcriber.CheckModel(cfg.DefaultModel, newJob(cfg).EnglishOnlyFits()); err != nil {
 		if _, ok := err.(transcriber.ErrModelNotFound); ok {
-			if err := promptAndDownloadModel(cfg.DefaultModel); err != nil {
-				return err
-			}
-		} else {
-			return err
+			return promptAndDownloadModel(cfg.DefaultModel)
 		}
+		return err
 	}
+	return nil
+}
 
-	events := make(chan pipeline.Event, 100)
+// interruptSignals stop a CLI run. The tools it runs are in process groups
+// of their own, which the terminal's Ctrl+C doesn't reach, so they are
+// stopped by cancelling their jobs.
+var interruptSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// errCancelled is the result of jobs stopped by an interrupt.
+var errCancelled = errors.New("cancelled")
+
+// jobResult summarizes the outcome of a single pipeline run.
+type jobResult struct {
+	Source      string
+	Title       string
+	OutputPaths []string
+	WordCount   int
+	Err         error
+}
 
+// queuedJob is a job together with the prefix for its output lines.
+type queuedJob struct {
+	job    *config.TranscriptionConfig
+	prefix string
+}
+
+// runJobs runs jobs concurrently on a shared queue, printing their events,
+// and returns the results in the order of jobs. Each line is prefixed with
+// the job's prefix so that interleaved output stays attributable.
+func runJobs(cfg *config.Config, jobs []queuedJob) []jobResult {
+	q := queue.New(queue.Options{
+		Workers:   cfg.Workers,
+		Downloads: cfg.MaxDownloads,
+		History:   history.Open(cfg.HistoryFile),
+		Cache:     cache.Open(cfg.CacheDir),
+	})
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, interruptSignals...)
+	defer signal.Stop(signals)
+
+	results := make([]jobResult, len(jobs))
+	byID := make(map[queue.JobID]int, len(jobs))
+	for i, j := range jobs {
+		results[i] = jobResult{Source: j.job.GetSource()}
+		byID[q.Submit(j.job)] = i
+	}
+	q.Close()
+
+	// An interrupt cancels the jobs, which then clean up before the event
+	// stream closes. A second one exits without waiting for that.
+	done := make(chan struct{})
+	defer close(done)
 	go func() {
-		p := pipeline.New(transcriptionCfg, events)
-		p.Run()
-		close(events)
+		select {
+		case <-signals:
+		case <-done:
+			return
+		}
+		fmt.Println("\nCancelling...")
+		q.CancelAll()
+		select {
+		case <-signals:
+			os.Exit(130)
+		case <-done:
+		}
 	}()
 
-	for event := range events {
-		switch e := event.(type) {
+	for qe := range q.Events() {
+		i := byID[qe.JobID]
+		result := &results[i]
+		prefix := jobs[i].prefix
+
+		switch e := qe.Event.(type) {
 		case pipeline.MetadataEvent:
-			fmt.Printf("Video: %s\n", e.Title)
-			fmt.Printf("Channel: %s\n", e.Channel)
-			fmt.Printf("Duration: %s\n\n", e.Duration)
+			result.Title = e.Title
+			fmt.Printf("%sVideo: %s\n", prefix, e.Title)
+			fmt.Printf("%sChannel: %s\n", prefix, e.Channel)
+			fmt.Printf("%sDuration: %s\n\n", prefix, e.Duration)
 		case pipeline.ProgressEvent:
-			fmt.Printf("[%s] %s (%.0f%%)\n", e.Step, e.Message, e.Progress*100)
+			fmt.Printf("%s[%s] %s (%.0f%%)\n", prefix, e.Step, e.Message, e.Progress*100)
 		case pipeline.CompletedEvent:
-			fmt.Printf("\nTranscription complete!\n")
-			fmt.Printf("Output: %s\n", e.OutputPath)
-			fmt.Printf("Words: %d\n", e.Stats.WordCount)
+			result.OutputPaths = e.OutputPaths
+			result.WordCount = e.Stats.WordCount
 		case pipeline.ErrorEvent:
-			return fmt.Errorf("%s: %w", e.Step, e.Err)
+			result.Err = fmt.Errorf("%s: %w", e.Step, e.Err)
+			// Unprefixed single jobs report their error on exit instead.
+			if prefix != "" {
+				fmt.Printf("%sFailed: %v\n", prefix, result.Err)
+			}
+		case pipeline.CancelledEvent:
+			result.Err = fmt.Errorf("%s: %w", e.Step, errCancelled)
+			if prefix != "" {
+				fmt.Printf("%sCancelled\n", prefix)
+			}
 		}
 	}
 
-	return nil
+	return results
 }
 
 func promptAndDownloadModel(modelName string) error {
@@ -170,9 +512,19 @@ func promptAndDownloadModel(modelName string) error {
 		return fmt.Errorf("model download cancelled")
 	}
 
-	fmt.Printf("\nDownloading %s...\n", modelName)
+	fmt.Println()
+	if err := downloadModel(modelName); err != nil {
+		return err
+	}
+	fmt.Println()
+	return nil
+}
+
+// downloadModel downloads a model, printing progress on a single line.
+func downloadModel(modelName string) error {
+	fmt.Printf("Downloading %s...\n", modelName)
 
-	err = models.Download(modelName, func(downloaded, total int64) {
+	err := models.Download(modelName, func(downloaded, total int64) {
 		if total > 0 {
 			pct := float64(downloaded) / float64(total) * 100
 			fmt.Printf("\r  %s / %s (%.1f%%)",
@@ -187,7 +539,7 @@ func promptAndDownloadModel(modelName string) error {
 		return fmt.Errorf("download failed: %w", err)
 	}
 
-	fmt.Printf("\n\nModel '%s' downloaded successfully!\n\n", modelName)
+	fmt.Printf("\n\nModel '%s' downloaded successfully!\n", modelName)
 	return nil
 }
 
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"io"
+	"testing"
+)
+
+func TestSubcommandHelp(t *testing.T) {
+	// Cobra panics when a subcommand's shorthand clashes with a
+	// persistent flag of the root command.
+	for _, sub := range []string{"batch", "history", "models", "cleanup"} {
+		t.Run(sub, func(t *testing.T) {
+			cmd := newRootCmd()
+			cmd.SetOut(io.Discard)
+			cmd.SetArgs([]string{sub, "--help"})
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("%s --help: %v", sub, err)
+			}
+		})
+	}
+}
+
+func TestBatchListShorthand(t *testing.T) {
+	cmd := newRootCmd()
+	batch, _, err := cmd.Find([]string{"batch"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { batchList, language = "", "" })
+
+	if err := batch.ParseFlags([]string{"-l", "talks.txt", "--language", "de"}); err != nil {
+		t.Fatal(err)
+	}
+	if batchList != "talks.txt" {
+		t.Errorf("-l should set --list, got %q", batchList)
+	}
+	if language != "de" {
+		t.Errorf("--language should still apply to batch, got %q", language)
+	}
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/cyber/whisper-transcribe/internal/models"
+	"github.com/cyber/whisper-transcribe/internal/transcriber"
+	"github.com/spf13/cobra"
+)
+
+var modelsRemoveAll bool
+
+func newModelsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "models",
+		Short: "List, download, remove and verify Whisper models",
+		Long: `Manage whisper.cpp models. Models are downloaded to the models
+directory (WHISPER_MODEL_PATH or ~/.cache/whisper) but are also picked up
+from the other search paths shown by "models list".`,
+		Args: cobra.NoArgs,
+		RunE: runModelsList,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "Show installed and available models with disk usage",
+		Args:  cobra.NoArgs,
+		RunE:  runModelsList,
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:          "pull <name>...",
+		Short:        "Download models",
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE:         runModelsPull,
+	})
+	rm := &cobra.Command{
+		Use:          "rm <name>...",
+		Short:        "Remove downloaded models",
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE:         runModelsRemove,
+	}
+	rm.Flags().BoolVar(&modelsRemoveAll, "all", false, "remove copies from every search path, not just the models directory")
+	cmd.AddCommand(rm)
+	cmd.AddCommand(&cobra.Command{
+		Use:          "verify [name...]",
+		Short:        "Check installed models against their checksums",
+		Args:         cobra.ArbitraryArgs,
+		SilenceUsage: true,
+		RunE:         runModelsVerify,
+	})
+
+	return cmd
+}
+
+func runModelsList(cmd *cobra.Command, args []string) error {
+	installed, err := models.FindInstalled()
+	if err != nil {
+		return err
+	}
+	printModels(os.Stdout, models.AvailableModels(), installed)
+	return nil
+}
+
+// printModels writes the model table followed by disk usage per search
+// path and any model stored more than once.
+func printModels(w io.Writer, available []models.ModelInfo, installed []models.InstalledFile) {
+	byFilename := make(map[string][]models.InstalledFile)
+	for _, f := range installed {
+		name := filepath.Base(f.Path)
+		byFilename[name] = append(byFilename[name], f)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tSIZE\tSTATUS\tLOCATION")
+	for _, m := range available {
+		copies := byFilename[m.Filename]
+		if len(copies) == 0 {
+			fmt.Fprintf(tw, "%s\t%s\tavailable\t-\n", m.Name, m.Size)
+			continue
+		}
+		for i, f := range copies {
+			status := "installed"
+			if i > 0 {
+				status = "duplicate"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", m.Name, models.FormatBytes(f.Size), status, f.Path)
+		}
+	}
+	for _, f := range installed {
+		if len(f.Models) == 0 {
+			fmt.Fprintf(tw, "%s\t%s\tunregistered\t%s\n", filepath.Base(f.Path), models.FormatBytes(f.Size), f.Path)
+		}
+	}
+	tw.Flush()
+
+	fmt.Fprintln(w, "\nSearch paths:")
+	var total int64
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, dir := range models.SearchPaths() {
+		var size int64
+		count := 0
+		for _, f := range installed {
+			if f.Dir == dir {
+				size += f.Size
+				count++
+			}
+		}
+		total += size
+		if count == 0 {
+			fmt.Fprintf(tw, "  %s\t-\n", dir)
+			continue
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t(%d files)\n", dir, models.FormatBytes(size), count)
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "\nTotal: %s in %d files\n", models.FormatBytes(total), len(installed))
+
+	var wasted int64
+	for _, copies := range byFilename {
+		for _, f := range copies[1:] {
+			wasted += f.Size
+		}
+	}
+	if wasted > 0 {
+		fmt.Fprintf(w, "Duplicates use %s; remove them with \"models rm --all\" and pull again.\n",
+			models.FormatBytes(wasted))
+	}
+}
+
+func runModelsPull(cmd *cobra.Command, args []string) error {
+	for _, name := range args {
+		if _, err := models.GetModelInfo(name); err != nil {
+			return err
+		}
+	}
+	for _, name := range args {
+		if path := transcriber.FindModel(name); path != "" {
+			fmt.Printf("Model '%s' is already installed at %s\n", name, path)
+			continue
+		}
+		if err := downloadModel(name); err != nil {
+			return err
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+func runModelsRemove(cmd *cobra.Command, args []string) error {
+	installed, err := models.FindInstalled()
+	if err != nil {
+		return err
+	}
+	modelsDir := filepath.Clean(models.GetModelsDir())
+
+	for _, name := range args {
+		info, err := models.GetModelInfo(name)
+		if err != nil {
+			return err
+		}
+
+		removed := 0
+		for _, f := range installed {
+			if filepath.Base(f.Path) != info.Filename {
+				continue
+			}
+			if !modelsRemoveAll && f.Dir != modelsDir {
+				fmt.Printf("Keeping %s (outside the models directory; use --all)\n", f.Path)
+				continue
+			}
+			if err := os.Remove(f.Path); err != nil {
+				return fmt.Errorf("remove %s: %w", f.Path, err)
+			}
+			fmt.Printf("Removed %s (%s)\n", f.Path, models.FormatBytes(f.Size))
+			removed++
+		}
+
+		// A partial download would otherwise be resumed by the next pull.
+		partial := filepath.Join(modelsDir, info.Filename+".tmp")
+		if err := os.Remove(partial); err == nil {
+			fmt.Printf("Removed partial download %s\n", partial)
+		}
+
+		if removed == 0 {
+			fmt.Printf("Model '%s' is not installed\n", name)
+		}
+	}
+	return nil
+}
+
+func runModelsVerify(cmd *cobra.Command, args []string) error {
+	installed, err := models.FindInstalled()
+	if err != nil {
+		return err
+	}
+
+	wanted := make(map[string]bool)
+	for _, name := range args {
+		info, err := models.GetModelInfo(name)
+		if err != nil {
+			return err
+		}
+		wanted[info.Filename] = true
+	}
+
+	var files []models.InstalledFile
+	for _, f := range installed {
+		if len(wanted) == 0 || wanted[filepath.Base(f.Path)] {
+			files = append(files, f)
+		}
+	}
+	if len(files) == 0 {
+		if len(args) > 0 {
+			return fmt.Errorf("%s not installed", strings.Join(args, ", "))
+		}
+		fmt.Println("No models installed.")
+		return nil
+	}
+
+	failed := 0
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STATUS\tMODEL\tPATH")
+	for _, f := range files {
+		if len(f.Models) == 0 || !f.Models[0].HasChecksum() {
+			fmt.Fprintf(tw, "UNKNOWN\t%s\t%s\n", f.Names(), f.Path)
+			continue
+		}
+
+		status := "OK"
+		if err := models.VerifyFile(f.Path, &f.Models[0]); err != nil {
+			failed++
+			status = "FAILED"
+			var sumErr *models.ChecksumError
+			if !errors.As(err, &sumErr) {
+				status = "ERROR: " + err.Error()
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", status, f.Names(), f.Path)
+	}
+	tw.Flush()
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d models failed verification; remove them with \"models rm\" and pull again", failed, len(files))
+	}
+	return nil
+}
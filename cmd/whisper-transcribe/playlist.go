@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/signal"
+
+	"github.com/cyber/whisper-transcribe/internal/config"
+	"github.com/cyber/whisper-transcribe/internal/downloader"
+	"github.com/cyber/whisper-transcribe/internal/formatter"
+)
+
+// playlistRun is an expanded playlist whose videos are transcribed into a
+// per-playlist subdirectory.
+type playlistRun struct {
+	url      string
+	playlist *downloader.Playlist
+	dir      string
+	prefix   string
+	jobs     []queuedJob
+}
+
+// preparePlaylist expands a playlist or channel URL into one job per video.
+func preparePlaylist(cfg *config.Config, playlistURL, prefix string) (*playlistRun, error) {
+	fmt.Printf("%sExpanding playlist %s\n", prefix, playlistURL)
+
+	ctx, stop := signal.NotifyContext(context.Background(), interruptSignals...)
+	defer stop()
+	playlist, err := downloader.ExpandPlaylist(ctx, playlistURL, playlistOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	run := &playlistRun{
+		url:      playlistURL,
+		playlist: playlist,
+		dir:      formatter.PlaylistDir(cfg.OutputDir, playlist.Title),
+		prefix:   prefix,
+	}
+	fmt.Printf("%sPlaylist: %s (%d videos)\n\n", prefix, playlist.Title, len(playlist.Entries))
+
+	for i, entry := range playlist.Entries {
+		job := newJob(cfg)
+		job.URL = entry.URL
+		job.OutputDir = run.dir
+		run.jobs = append(run.jobs, queuedJob{
+			job:    job,
+			prefix: fmt.Sprintf("%s[%d/%d] ", prefix, i+1, len(playlist.Entries)),
+		})
+	}
+
+	return run, nil
+}
+
+// writeIndex writes the playlist index for the results of the run's jobs.
+func (r *playlistRun) writeIndex(results []jobResult) error {
+	index := make([]formatter.IndexEntry, 0, len(results))
+	for i, result := range results {
+		entry := r.playlist.Entries[i]
+		title := result.Title
+		if title == "" {
+			title = entry.Title
+		}
+		index = append(index, formatter.IndexEntry{
+			Title: title,
+			URL:   entry.URL,
+			Path:  formatter.PrimaryOutput(result.OutputPaths),
+		})
+	}
+
+	indexPath, err := formatter.GeneratePlaylistIndex(r.playlist.Title, r.url, r.dir, index)
+	if err != nil {
+		return fmt.Errorf("write playlist index: %w", err)
+	}
+	fmt.Printf("%sIndex: %s\n\n", r.prefix, indexPath)
+	return nil
+}
+
+// runPlaylist transcribes every video of a playlist or channel, then writes
+// an index linking them all.
+func runPlaylist(cfg *config.Config, playlistURL string) ([]jobResult, error) {
+	run, err := preparePlaylist(cfg, playlistURL, "")
+	if err != nil {
+		return nil, err
+	}
+
+	results := runJobs(cfg, run.jobs)
+	fmt.Println()
+	if err := run.writeIndex(results); err != nil {
+		return results, err
+	}
+	return results, nil
+}
+
+func playlistOptions() downloader.PlaylistOptions {
+	return downloader.PlaylistOptions{
+		Items:     playlistItems,
+		DateAfter: dateAfter,
+		MaxCount:  maxVideos,
+	}
+}
@@ -0,0 +1,253 @@
+package audio
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cyber/whisper-transcribe/internal/procgroup"
+)
+
+// Options control how audio is prepared for whisper.
+type Options struct {
+	// Normalize evens out loudness with ffmpeg's loudnorm filter.
+	Normalize bool `mapstructure:"normalize"`
+	// Denoise removes rumble below the speech range and broadband noise.
+	Denoise bool `mapstructure:"denoise"`
+	// TrimSilence drops leading and trailing silence.
+	TrimSilence bool `mapstructure:"trim_silence"`
+	// Stereo keeps two channels, which stereo diarization needs. It
+	// follows from the diarization mode rather than configuration.
+	Stereo bool `mapstructure:"-"`
+	// Start and End cut out part of the input; zero End means its end.
+	// They are set per job.
+	Start time.Duration `mapstructure:"-"`
+	End   time.Duration `mapstructure:"-"`
+}
+
+// Filters returns the ffmpeg audio filters for the options, in the order
+// they are applied.
+func (o Options) Filters() []string {
+	var filters []string
+	if o.Denoise {
+		filters = append(filters, "highpass=f=80", "afftdn=nf=-25")
+	}
+	if o.Normalize {
+		filters = append(filters, "loudnorm=I=-16:TP=-1.5:LRA=11")
+	}
+	return filters
+}
+
+// String describes the options that change the audio, e.g. for cache keys.
+// It is empty if the audio is only converted.
+func (o Options) String() string {
+	var parts []string
+	if o.Normalize {
+		parts = append(parts, "normalize")
+	}
+	if o.Denoise {
+		parts = append(parts, "denoise")
+	}
+	if o.TrimSilence {
+		parts = append(parts, "trim")
+	}
+	return strings.Join(parts, ",")
+}
+
+func (o Options) clipped() bool {
+	return o.Start > 0 || o.End > 0
+}
+
+func (o Options) channels() uint16 {
+	if o.Stereo {
+		return 2
+	}
+	return 1
+}
+
+// Result is audio ready for whisper.
+type Result struct {
+	Path string
+	// Offset is where in the input the audio starts, after clipping and
+	// trimming leading silence. Timestamps in the audio are shifted by it.
+	Offset time.Duration
+	// Converted is false if the input was used as is.
+	Converted bool
+}
+
+// ProgressFunc is called with preparation progress (0.0 to 1.0).
+type ProgressFunc func(progress float64)
+
+// Prepare converts input, any format ffmpeg reads, to 16 kHz 16-bit PCM
+// WAV in dir, cutting out the time range and applying the filters of opts.
+// Input that already has that format and needs no changes is returned
+// unchanged.
+func Prepare(ctx context.Context, input, dir string, opts Options, onProgress ProgressFunc) (*Result, error) {
+	if !opts.TrimSilence && !opts.clipped() && len(opts.Filters()) == 0 {
+		if h, err := ReadHeader(input); err == nil && h.Format == 1 &&
+			h.SampleRate == WhisperSampleRate && h.BitsPerSample == 16 && h.Channels == opts.channels() {
+			return &Result{Path: input}, nil
+		}
+	}
+
+	// Trimming takes a fraction of the time of decoding and filtering.
+	convertShare := 1.0
+	if opts.TrimSilence {
+		convertShare = 0.9
+	}
+
+	converted := filepath.Join(dir, "audio.wav")
+	err := convert(ctx, input, converted, opts, func(p float64) {
+		if onProgress != nil {
+			onProgress(p * convertShare)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !opts.TrimSilence {
+		return &Result{Path: converted, Offset: opts.Start, Converted: true}, nil
+	}
+
+	trimmed := filepath.Join(dir, "audio-trimmed.wav")
+	offset, err := TrimSilence(converted, trimmed)
+	if err != nil {
+		return nil, err
+	}
+	os.Remove(converted)
+	return &Result{Path: trimmed, Offset: opts.Start + offset, Converted: true}, nil
+}
+
+var (
+	ffmpegDurationRe = regexp.MustCompile(`Duration:\s*(\d+):(\d{2}):(\d{2}(?:\.\d+)?)`)
+	ffmpegOutTimeRe  = regexp.MustCompile(`^out_time_(?:us|ms)=(\d+)`)
+)
+
+// convert runs ffmpeg, reporting progress from its -progress output against
+// the input duration it logs.
+func convert(ctx context.Context, input, output string, opts Options, onProgress ProgressFunc) error {
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		return fmt.Errorf("ffmpeg not found in PATH")
+	}
+
+	cmd := procgroup.Command(ctx, "ffmpeg", ffmpegArgs(input, output, opts)...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("stdout pipe: %w", err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return fmt.Errorf("stderr pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start ffmpeg: %w", err)
+	}
+
+	var (
+		mu       sync.Mutex
+		duration time.Duration
+		lastLine string
+	)
+	stderrDone := make(chan struct{})
+	go func() {
+		defer close(stderrDone)
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			mu.Lock()
+			if m := ffmpegDurationRe.FindStringSubmatch(line); m != nil && duration == 0 {
+				duration = parseClock(m[1], m[2], m[3])
+			}
+			if line != "" {
+				lastLine = line
+			}
+			mu.Unlock()
+		}
+	}()
+
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		m := ffmpegOutTimeRe.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		// Both keys are in microseconds despite the name of out_time_ms.
+		us, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		mu.Lock()
+		total := duration
+		mu.Unlock()
+		if opts.clipped() {
+			// Only the clip is decoded.
+			total = clipLength(total, opts)
+		}
+		if total > 0 && onProgress != nil {
+			onProgress(min(float64(us)/float64(total.Microseconds()), 1))
+		}
+	}
+
+	<-stderrDone
+	if err := cmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("ffmpeg failed: %w: %s", err, lastLine)
+	}
+	return nil
+}
+
+// ffmpegArgs returns the arguments converting input to whisper's format.
+func ffmpegArgs(input, output string, opts Options) []string {
+	args := []string{"-hide_banner", "-nostdin", "-y"}
+	if opts.Start > 0 {
+		// Seeking before -i is fast and, for audio, exact.
+		args = append(args, "-ss", formatSeconds(opts.Start))
+	}
+	args = append(args, "-i", input, "-vn")
+	if opts.End > 0 {
+		args = append(args, "-t", formatSeconds(opts.End-opts.Start))
+	}
+	if filters := opts.Filters(); len(filters) > 0 {
+		args = append(args, "-af", strings.Join(filters, ","))
+	}
+	return append(args,
+		"-ac", strconv.Itoa(int(opts.channels())),
+		"-ar", strconv.Itoa(WhisperSampleRate),
+		"-c:a", "pcm_s16le",
+		"-progress", "pipe:1",
+		"-nostats",
+		output,
+	)
+}
+
+// clipLength returns how much of input of the given length the clip in
+// opts covers.
+func clipLength(total time.Duration, opts Options) time.Duration {
+	end := total
+	if opts.End > 0 && opts.End < end {
+		end = opts.End
+	}
+	return max(end-opts.Start, 0)
+}
+
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
+
+func parseClock(h, m, s string) time.Duration {
+	hours, _ := strconv.Atoi(h)
+	minutes, _ := strconv.Atoi(m)
+	secs, _ := strconv.ParseFloat(s, 64)
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute +
+		time.Duration(secs*float64(time.Second))
+}
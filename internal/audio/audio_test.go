@@ -0,0 +1,195 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// writeWAV writes 16-bit PCM samples as a WAV file.
+func writeWAV(t *testing.T, channels uint16, sampleRate uint32, samples []int16) string {
+	t.Helper()
+
+	var b bytes.Buffer
+	if err := writeHeader(&b, channels, sampleRate, uint32(len(samples)*2)); err != nil {
+		t.Fatal(err)
+	}
+	binary.Write(&b, binary.LittleEndian, samples)
+
+	path := filepath.Join(t.TempDir(), "test.wav")
+	if err := os.WriteFile(path, b.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// tone returns n samples of a square wave, loud enough to count as speech.
+func tone(n int) []int16 {
+	samples := make([]int16, n)
+	for i := range samples {
+		samples[i] = 8000
+		if i%40 < 20 {
+			samples[i] = -8000
+		}
+	}
+	return samples
+}
+
+func TestReadHeader(t *testing.T) {
+	path := writeWAV(t, 2, 16000, make([]int16, 64000))
+
+	h, err := ReadHeader(path)
+	if err != nil {
+		t.Fatalf("ReadHeader failed: %v", err)
+	}
+	if h.Channels != 2 || h.SampleRate != 16000 || h.BitsPerSample != 16 || h.DataOffset != 44 {
+		t.Errorf("unexpected header: %+v", h)
+	}
+	if h.Duration() != 2*time.Second {
+		t.Errorf("expected 2s, got %v", h.Duration())
+	}
+	if h.IsWhisperFormat() {
+		t.Error("stereo audio is not whisper's format")
+	}
+}
+
+func TestTrimSilence(t *testing.T) {
+	// 0.9s of silence, 0.3s of tone, 2s of silence at 16 kHz, so that the
+	// tone starts and ends on frame boundaries.
+	samples := append(make([]int16, 14400), tone(4800)...)
+	samples = append(samples, make([]int16, 32000)...)
+	in := writeWAV(t, 1, 16000, samples)
+	out := filepath.Join(t.TempDir(), "trimmed.wav")
+
+	offset, err := TrimSilence(in, out)
+	if err != nil {
+		t.Fatalf("TrimSilence failed: %v", err)
+	}
+	if want := 900*time.Millisecond - silencePadding; offset != want {
+		t.Errorf("expected offset %v, got %v", want, offset)
+	}
+
+	h, err := ReadHeader(out)
+	if err != nil {
+		t.Fatalf("ReadHeader failed: %v", err)
+	}
+	if want := 300*time.Millisecond + 2*silencePadding; h.Duration() != want {
+		t.Errorf("expected %v of audio, got %v", want, h.Duration())
+	}
+}
+
+func TestTrimSilenceAllSilent(t *testing.T) {
+	in := writeWAV(t, 1, 16000, make([]int16, 16000))
+	out := filepath.Join(t.TempDir(), "trimmed.wav")
+
+	offset, err := TrimSilence(in, out)
+	if err != nil {
+		t.Fatalf("TrimSilence failed: %v", err)
+	}
+	h, err := ReadHeader(out)
+	if err != nil {
+		t.Fatalf("ReadHeader failed: %v", err)
+	}
+	if offset != 0 || h.Duration() != time.Second {
+		t.Errorf("expected silent audio unchanged, got offset %v and %v", offset, h.Duration())
+	}
+}
+
+func TestPrepareSkipsWhisperFormat(t *testing.T) {
+	in := writeWAV(t, 1, 16000, tone(1600))
+
+	res, err := Prepare(context.Background(), in, t.TempDir(), Options{}, nil)
+	if err != nil {
+		t.Fatalf("Prepare failed: %v", err)
+	}
+	if res.Path != in || res.Converted {
+		t.Errorf("expected input to be used as is, got %+v", res)
+	}
+}
+
+func TestFFmpegArgs(t *testing.T) {
+	args := ffmpegArgs("in.m4a", "out.wav", Options{Normalize: true, Denoise: true, Stereo: true})
+
+	i := slices.Index(args, "-af")
+	if i < 0 || args[i+1] != "highpass=f=80,afftdn=nf=-25,loudnorm=I=-16:TP=-1.5:LRA=11" {
+		t.Errorf("unexpected filters: %v", args)
+	}
+	if i := slices.Index(args, "-ac"); i < 0 || args[i+1] != "2" {
+		t.Errorf("expected stereo output: %v", args)
+	}
+	if args[len(args)-1] != "out.wav" {
+		t.Errorf("expected output last: %v", args)
+	}
+
+	if slices.Contains(ffmpegArgs("in.mp3", "out.wav", Options{}), "-af") {
+		t.Error("expected no filters without options")
+	}
+
+	// Clipping seeks the input and limits the output's length.
+	args = ffmpegArgs("in.mp3", "out.wav", Options{Start: 10 * time.Minute, End: 20 * time.Minute})
+	if i := slices.Index(args, "-ss"); i < 0 || args[i+1] != "600.000" || i > slices.Index(args, "-i") {
+		t.Errorf("expected input seek to 600s: %v", args)
+	}
+	if i := slices.Index(args, "-t"); i < 0 || args[i+1] != "600.000" {
+		t.Errorf("expected a length of 600s: %v", args)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	// Three 1.5s tones separated by 0.3s pauses, in 30ms frames of 480
+	// samples.
+	var samples []int16
+	for i := 0; i < 3; i++ {
+		if i > 0 {
+			samples = append(samples, make([]int16, 10*480)...)
+		}
+		samples = append(samples, tone(50*480)...)
+	}
+	in := writeWAV(t, 1, 16000, samples)
+
+	chunks, err := Split(in, t.TempDir(), 2*time.Second)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks, got %d", len(chunks))
+	}
+
+	// Cuts land in the middle of the pauses within the last 20% of each
+	// chunk: frames 53-59 and 110-119.
+	wantOffsets := []time.Duration{0, 56 * frameDuration, 115 * frameDuration}
+	var total time.Duration
+	for i, c := range chunks {
+		if c.Offset != wantOffsets[i] {
+			t.Errorf("chunk %d: expected offset %v, got %v", i, wantOffsets[i], c.Offset)
+		}
+		h, err := ReadHeader(c.Path)
+		if err != nil {
+			t.Fatalf("ReadHeader failed: %v", err)
+		}
+		if h.Duration() != c.Duration || c.Duration > 2*time.Second {
+			t.Errorf("chunk %d: unexpected duration %v (file %v)", i, c.Duration, h.Duration())
+		}
+		total += c.Duration
+	}
+	if total != 170*frameDuration {
+		t.Errorf("chunks don't add up to the input: %v", total)
+	}
+}
+
+func TestSplitShortAudio(t *testing.T) {
+	in := writeWAV(t, 1, 16000, tone(16000))
+
+	chunks, err := Split(in, t.TempDir(), time.Minute)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	if len(chunks) != 1 || chunks[0].Path != in || chunks[0].Duration != time.Second {
+		t.Errorf("expected the input as a single chunk, got %+v", chunks)
+	}
+}
@@ -0,0 +1,123 @@
+package audio
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+)
+
+const (
+	// cutWindow is the share of the chunk length at its end in which a
+	// cut is looked for.
+	cutWindow = 0.2
+	// noiseMargin is how far above the noise floor, in dB, a frame may be
+	// and still count as a pause.
+	noiseMargin = 10.0
+)
+
+// Chunk is a piece of a longer recording.
+type Chunk struct {
+	Path string
+	// Offset is where the chunk starts in the recording.
+	Offset   time.Duration
+	Duration time.Duration
+}
+
+// Split cuts the 16-bit PCM WAV at path into chunks of at most length,
+// written to dir. Cuts are placed in pauses near the end of each chunk, so
+// that no word is split and the chunks can be transcribed independently.
+// Audio no longer than length is returned as a single chunk without
+// copying.
+func Split(path, dir string, length time.Duration) ([]Chunk, error) {
+	h, err := readPCMHeader(path)
+	if err != nil {
+		return nil, err
+	}
+	if length <= 0 || h.Duration() <= length {
+		return []Chunk{{Path: path, Duration: h.Duration()}}, nil
+	}
+
+	levels, err := frameLevels(path, h)
+	if err != nil {
+		return nil, err
+	}
+	frame := frameSamples(h)
+	cuts := cutPoints(levels, int(length/frameDuration))
+
+	var chunks []Chunk
+	start := int64(0)
+	for i, cut := range append(cuts, len(levels)) {
+		end := min(int64(cut)*frame, h.samples())
+		out := filepath.Join(dir, fmt.Sprintf("chunk-%03d.wav", i))
+		if err := copyRange(path, h, out, start, end); err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, Chunk{
+			Path:     out,
+			Offset:   h.sampleTime(start),
+			Duration: h.sampleTime(end - start),
+		})
+		start = end
+	}
+	return chunks, nil
+}
+
+// cutPoints returns the frames at which to cut so that no chunk is longer
+// than maxFrames. Each cut is in the middle of the longest pause in the
+// last part of the chunk, or at its quietest frame if there is no pause.
+func cutPoints(levels []float64, maxFrames int) []int {
+	threshold := pauseThreshold(levels)
+	window := max(int(float64(maxFrames)*cutWindow), 1)
+
+	var cuts []int
+	start := 0
+	for len(levels)-start > maxFrames {
+		lo, hi := start+maxFrames-window, start+maxFrames
+
+		cut, longest := -1, 0
+		quietest := lo
+		for i := lo; i < hi; {
+			if levels[i] < levels[quietest] {
+				quietest = i
+			}
+			if levels[i] >= threshold {
+				i++
+				continue
+			}
+			j := i
+			for j < hi && levels[j] < threshold {
+				if levels[j] < levels[quietest] {
+					quietest = j
+				}
+				j++
+			}
+			if j-i > longest {
+				cut, longest = (i+j)/2, j-i
+			}
+			i = j
+		}
+		if cut < 0 {
+			cut = quietest
+		}
+		// A cut at the window's start still has to make progress.
+		cut = max(cut, start+1)
+		cuts = append(cuts, cut)
+		start = cut
+	}
+	return cuts
+}
+
+// pauseThreshold estimates the level below which a frame is a pause from
+// the recording's noise floor, taken as its tenth percentile level. It is
+// never lower than the level of silence.
+func pauseThreshold(levels []float64) float64 {
+	if len(levels) == 0 {
+		return silenceThreshold
+	}
+	sorted := slices.Clone(levels)
+	sort.Float64s(sorted)
+	floor := sorted[len(sorted)/10]
+	return max(floor+noiseMargin, silenceThreshold)
+}
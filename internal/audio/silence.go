@@ -0,0 +1,182 @@
+package audio
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"time"
+)
+
+const (
+	// frameDuration is the window over which loudness is measured.
+	frameDuration = 30 * time.Millisecond
+	// silenceThreshold is the RMS level in dBFS below which a frame
+	// counts as silent.
+	silenceThreshold = -50.0
+	// silencePadding is kept around speech so that trimming doesn't clip
+	// soft word onsets and endings.
+	silencePadding = 200 * time.Millisecond
+)
+
+// TrimSilence copies the 16-bit PCM WAV in to out without its leading and
+// trailing silence. It returns where in the input the copy starts. A file
+// that is silent throughout is copied unchanged.
+func TrimSilence(in, out string) (time.Duration, error) {
+	h, err := readPCMHeader(in)
+	if err != nil {
+		return 0, err
+	}
+	levels, err := frameLevels(in, h)
+	if err != nil {
+		return 0, err
+	}
+
+	frame := frameSamples(h)
+	total := h.samples()
+	start, end := int64(0), total
+	first, last := -1, -1
+	for i, l := range levels {
+		if l >= silenceThreshold {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first >= 0 {
+		pad := int64(silencePadding.Seconds() * float64(h.SampleRate))
+		start = max(int64(first)*frame-pad, 0)
+		end = min(int64(last+1)*frame+pad, total)
+	}
+
+	if err := copyRange(in, h, out, start, end); err != nil {
+		return 0, err
+	}
+	return h.sampleTime(start), nil
+}
+
+// readPCMHeader reads the header of a WAV file the silence detection can
+// handle.
+func readPCMHeader(path string) (*Header, error) {
+	h, err := ReadHeader(path)
+	if err != nil {
+		return nil, err
+	}
+	if h.Format != 1 || h.BitsPerSample != 16 {
+		return nil, fmt.Errorf("%s: expected 16-bit PCM", path)
+	}
+	return h, nil
+}
+
+// samples returns the number of samples per channel.
+func (h *Header) samples() int64 {
+	return int64(h.DataSize) / int64(h.BlockAlign())
+}
+
+// sampleTime returns the time of sample n.
+func (h *Header) sampleTime(n int64) time.Duration {
+	return time.Duration(n) * time.Second / time.Duration(h.SampleRate)
+}
+
+// frameSamples returns the number of samples per channel in a frame.
+func frameSamples(h *Header) int64 {
+	return int64(frameDuration.Seconds() * float64(h.SampleRate))
+}
+
+// frameLevels returns the RMS level in dBFS of each frame of the audio,
+// across all channels. The last frame may be shorter.
+func frameLevels(path string, h *Header) ([]float64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if _, err := f.Seek(h.DataOffset, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	channels := int(h.Channels)
+	buf := make([]int16, frameSamples(h)*int64(channels))
+	raw := make([]byte, len(buf)*2)
+	r := bufio.NewReaderSize(io.LimitReader(f, int64(h.DataSize)), 1<<16)
+
+	levels := make([]float64, 0, h.samples()/frameSamples(h)+1)
+	for {
+		n, err := readSamples(r, raw, buf)
+		if n > 0 {
+			levels = append(levels, level(buf[:n]))
+		}
+		if err == io.EOF {
+			return levels, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read audio: %w", err)
+		}
+	}
+}
+
+// copyRange writes samples [start, end) of the WAV in as a new WAV out.
+func copyRange(in string, h *Header, out string, start, end int64) error {
+	src, err := os.Open(in)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	block := int64(h.BlockAlign())
+	if _, err := src.Seek(h.DataOffset+start*block, io.SeekStart); err != nil {
+		return err
+	}
+
+	dst, err := os.Create(out)
+	if err != nil {
+		return fmt.Errorf("create audio: %w", err)
+	}
+	size := (end - start) * block
+	if err := writeHeader(dst, h.Channels, h.SampleRate, uint32(size)); err != nil {
+		dst.Close()
+		return fmt.Errorf("write audio: %w", err)
+	}
+	if _, err := io.CopyN(dst, src, size); err != nil {
+		dst.Close()
+		return fmt.Errorf("write audio: %w", err)
+	}
+	if err := dst.Close(); err != nil {
+		return fmt.Errorf("write audio: %w", err)
+	}
+	return nil
+}
+
+// readSamples fills buf with little-endian samples, using raw, which is
+// twice as long, for the bytes. It returns how many samples were read and
+// io.EOF once the input is exhausted.
+func readSamples(r io.Reader, raw []byte, buf []int16) (int, error) {
+	n, err := io.ReadFull(r, raw)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	n /= 2
+	for i := 0; i < n; i++ {
+		buf[i] = int16(binary.LittleEndian.Uint16(raw[2*i:]))
+	}
+	return n, err
+}
+
+// level returns the RMS level of samples in dBFS.
+func level(samples []int16) float64 {
+	if len(samples) == 0 {
+		return math.Inf(-1)
+	}
+	var sum float64
+	for _, s := range samples {
+		v := float64(s) / 32768
+		sum += v * v
+	}
+	rms := math.Sqrt(sum / float64(len(samples)))
+	if rms == 0 {
+		return math.Inf(-1)
+	}
+	return 20 * math.Log10(rms)
+}
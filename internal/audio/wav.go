@@ -0,0 +1,127 @@
+// Package audio converts and cleans up audio for whisper, which expects
+// 16 kHz mono 16-bit PCM WAV.
+package audio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// WhisperSampleRate is the sample rate whisper.cpp requires.
+const WhisperSampleRate = 16000
+
+// Header describes the format and data chunk of a WAV file.
+type Header struct {
+	Format        uint16
+	Channels      uint16
+	SampleRate    uint32
+	ByteRate      uint32
+	BitsPerSample uint16
+	// DataOffset is where the samples start in the file.
+	DataOffset int64
+	DataSize   uint32
+}
+
+// Duration returns the length of the audio.
+func (h *Header) Duration() time.Duration {
+	if h.ByteRate == 0 {
+		return 0
+	}
+	return time.Duration(float64(h.DataSize) / float64(h.ByteRate) * float64(time.Second))
+}
+
+// IsWhisperFormat reports whether the audio can be given to whisper as is.
+func (h *Header) IsWhisperFormat() bool {
+	return h.Format == 1 && h.Channels == 1 && h.SampleRate == WhisperSampleRate && h.BitsPerSample == 16
+}
+
+// ReadHeader reads the header of a WAV file.
+func ReadHeader(path string) (*Header, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var riff [12]byte
+	if _, err := io.ReadFull(f, riff[:]); err != nil {
+		return nil, fmt.Errorf("read wav header: %w", err)
+	}
+	if string(riff[0:4]) != "RIFF" || string(riff[8:12]) != "WAVE" {
+		return nil, fmt.Errorf("not a WAV file")
+	}
+
+	var h Header
+	haveFmt := false
+	for {
+		var hdr [8]byte
+		if _, err := io.ReadFull(f, hdr[:]); err != nil {
+			return nil, fmt.Errorf("read wav chunk: %w", err)
+		}
+		id := string(hdr[0:4])
+		size := binary.LittleEndian.Uint32(hdr[4:8])
+
+		switch id {
+		case "fmt ":
+			var fmtChunk [16]byte
+			if size < 16 {
+				return nil, fmt.Errorf("invalid fmt chunk")
+			}
+			if _, err := io.ReadFull(f, fmtChunk[:]); err != nil {
+				return nil, fmt.Errorf("read fmt chunk: %w", err)
+			}
+			le := binary.LittleEndian
+			h.Format = le.Uint16(fmtChunk[0:2])
+			h.Channels = le.Uint16(fmtChunk[2:4])
+			h.SampleRate = le.Uint32(fmtChunk[4:8])
+			h.ByteRate = le.Uint32(fmtChunk[8:12])
+			h.BitsPerSample = le.Uint16(fmtChunk[14:16])
+			haveFmt = true
+			if _, err := f.Seek(int64(size-16)+int64(size%2), io.SeekCurrent); err != nil {
+				return nil, err
+			}
+		case "data":
+			if !haveFmt {
+				return nil, fmt.Errorf("data chunk before fmt chunk")
+			}
+			offset, err := f.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return nil, err
+			}
+			h.DataOffset = offset
+			h.DataSize = size
+			return &h, nil
+		default:
+			// Chunks are word-aligned.
+			if _, err := f.Seek(int64(size)+int64(size%2), io.SeekCurrent); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// BlockAlign returns the size in bytes of one sample across all channels.
+func (h *Header) BlockAlign() int {
+	return int(h.Channels) * int(h.BitsPerSample) / 8
+}
+
+// writeHeader writes a canonical 44-byte header for 16-bit PCM.
+func writeHeader(w io.Writer, channels uint16, sampleRate uint32, dataSize uint32) error {
+	le := binary.LittleEndian
+	blockAlign := channels * 2
+	fields := []any{
+		[]byte("RIFF"), 36 + dataSize, []byte("WAVE"),
+		[]byte("fmt "), uint32(16), uint16(1), channels, sampleRate,
+		sampleRate * uint32(blockAlign), blockAlign, uint16(16),
+		[]byte("data"), dataSize,
+	}
+	for _, f := range fields {
+		if err := binary.Write(w, le, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
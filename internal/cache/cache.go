@@ -0,0 +1,140 @@
+// Package cache stores raw transcription segments so that re-running a job
+// for the same video or file and model can skip downloading and whisper and
+// only re-render the output.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cyber/whisper-transcribe/internal/downloader"
+	"github.com/cyber/whisper-transcribe/internal/transcriber"
+)
+
+// Key identifies a cached transcription.
+type Key struct {
+	// Source is "youtube:<video id>" or "sha256:<file hash>".
+	Source string
+	Model  string
+	// Options holds other settings that change whisper's output, such as
+	// the language and task.
+	Options string
+}
+
+// Entry is a cached transcription.
+type Entry struct {
+	Key       Key                   `json:"key"`
+	Metadata  downloader.Metadata   `json:"metadata"`
+	Segments  []transcriber.Segment `json:"segments"`
+	CreatedAt time.Time             `json:"created_at"`
+}
+
+// Cache is a directory of cached transcriptions, one JSON file per key.
+type Cache struct {
+	dir string
+}
+
+// DefaultDir returns the cache location, following the XDG cache directory
+// convention.
+func DefaultDir() string {
+	dir := os.Getenv("XDG_CACHE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return filepath.Join(os.TempDir(), "whisper-transcribe", "transcripts")
+		}
+		dir = filepath.Join(home, ".cache")
+	}
+	return filepath.Join(dir, "whisper-transcribe", "transcripts")
+}
+
+// Open returns the cache in dir, or in DefaultDir if dir is empty.
+func Open(dir string) *Cache {
+	if dir == "" {
+		dir = DefaultDir()
+	}
+	return &Cache{dir: dir}
+}
+
+// VideoKey returns the key for a YouTube video.
+func VideoKey(videoID, model string) Key {
+	return Key{Source: "youtube:" + videoID, Model: model}
+}
+
+// FileKey returns the key for a local file, derived from its content so
+// that renamed or moved files still hit the cache.
+func FileKey(path, model string) (Key, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Key{}, fmt.Errorf("open file: %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return Key{}, fmt.Errorf("hash file: %w", err)
+	}
+	return Key{Source: "sha256:" + hex.EncodeToString(h.Sum(nil)), Model: model}, nil
+}
+
+// Get returns the cached entry for key. A missing entry is not an error;
+// it returns nil.
+func (c *Cache) Get(key Key) (*Entry, error) {
+	data, err := os.ReadFile(c.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read cache: %w", err)
+	}
+
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, fmt.Errorf("parse cache: %w", err)
+	}
+	if e.Key != key {
+		// Hash collision or a hand-edited file; treat it as a miss.
+		return nil, nil
+	}
+	return &e, nil
+}
+
+// Put stores an entry, replacing any previous one for the same key.
+func (c *Cache) Put(e Entry) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return fmt.Errorf("create cache dir: %w", err)
+	}
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now()
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encode cache: %w", err)
+	}
+
+	path := c.path(e.Key)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("write cache: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("write cache: %w", err)
+	}
+	return nil
+}
+
+// path names the cache file after a hash of the key, which keeps arbitrary
+// sources and model names safe to use as file names.
+func (c *Cache) path(key Key) string {
+	sum := sha256.Sum256([]byte(key.Source + "\n" + key.Model + "\n" + key.Options))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:16])+".json")
+}
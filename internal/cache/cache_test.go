@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cyber/whisper-transcribe/internal/downloader"
+	"github.com/cyber/whisper-transcribe/internal/transcriber"
+)
+
+func TestCacheRoundTrip(t *testing.T) {
+	c := Open(t.TempDir())
+	key := VideoKey("dQw4w9WgXcQ", "base")
+
+	got, err := c.Get(key)
+	if err != nil || got != nil {
+		t.Fatalf("Get on empty cache = %v, %v; want nil, nil", got, err)
+	}
+
+	segments := []transcriber.Segment{
+		{Start: "00:00:00.000", End: "00:00:02.000", Text: "Hello", Language: "en", Probability: 0.9},
+		{Start: "00:00:02.000", End: "00:00:04.000", Text: "world"},
+	}
+	err = c.Put(Entry{Key: key, Metadata: downloader.Metadata{Title: "Test", VideoID: "dQw4w9WgXcQ"}, Segments: segments})
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	got, err = c.Get(key)
+	if err != nil || got == nil {
+		t.Fatalf("Get after Put = %v, %v", got, err)
+	}
+	if len(got.Segments) != 2 || got.Segments[0].Text != "Hello" || got.Segments[0].Language != "en" {
+		t.Errorf("segments = %+v", got.Segments)
+	}
+	if got.CreatedAt.IsZero() {
+		t.Error("CreatedAt not set")
+	}
+
+	// The model is part of the key.
+	if other, _ := c.Get(VideoKey("dQw4w9WgXcQ", "small")); other != nil {
+		t.Error("hit for a different model")
+	}
+
+	// So are the language and task.
+	translated := key
+	translated.Options = "language=de task=translate"
+	if other, _ := c.Get(translated); other != nil {
+		t.Error("hit for different options")
+	}
+}
+
+func TestFileKey(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.wav")
+	b := filepath.Join(dir, "renamed.wav")
+	c := filepath.Join(dir, "other.wav")
+	os.WriteFile(a, []byte("same audio"), 0644)
+	os.WriteFile(b, []byte("same audio"), 0644)
+	os.WriteFile(c, []byte("different audio"), 0644)
+
+	ka, err := FileKey(a, "base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	kb, _ := FileKey(b, "base")
+	kc, _ := FileKey(c, "base")
+
+	if ka != kb {
+		t.Errorf("same content gave different keys: %v, %v", ka, kb)
+	}
+	if ka == kc {
+		t.Error("different content gave the same key")
+	}
+	if _, err := FileKey(filepath.Join(dir, "missing.wav"), "base"); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseClipTime parses a position in the media as seconds ("90"),
+// MM:SS ("1:30"), HH:MM:SS ("1:02:03", optionally with fractional seconds)
+// or a Go duration ("1h2m3s"). An empty string is zero.
+func ParseClipTime(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil && strings.ContainsAny(s, "hms") {
+		if d < 0 {
+			return 0, fmt.Errorf("invalid time %q: negative", s)
+		}
+		return d, nil
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid time %q (use seconds, MM:SS or HH:MM:SS)", s)
+	}
+	var d time.Duration
+	for i, part := range parts {
+		last := i == len(parts)-1
+		var v float64
+		var err error
+		if last {
+			v, err = strconv.ParseFloat(part, 64)
+		} else {
+			var n int
+			n, err = strconv.Atoi(part)
+			v = float64(n)
+		}
+		if err != nil || v < 0 || (i > 0 && v >= 60) {
+			return 0, fmt.Errorf("invalid time %q (use seconds, MM:SS or HH:MM:SS)", s)
+		}
+		d = d*60 + time.Duration(v*float64(time.Second))
+	}
+	return d.Round(time.Millisecond), nil
+}
+
+// FormatClipTime formats a position in the media as HH:MM:SS, with
+// milliseconds only if there are any.
+func FormatClipTime(d time.Duration) string {
+	ms := d.Milliseconds()
+	s := fmt.Sprintf("%02d:%02d:%02d", ms/3_600_000, ms%3_600_000/60_000, ms%60_000/1000)
+	if ms%1000 != 0 {
+		s += fmt.Sprintf(".%03d", ms%1000)
+	}
+	return s
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseClipTime(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"", 0},
+		{"90", 90 * time.Second},
+		{"1:30", 90 * time.Second},
+		{"01:02:03", time.Hour + 2*time.Minute + 3*time.Second},
+		{"0:10:00.5", 10*time.Minute + 500*time.Millisecond},
+		{"1h30m", 90 * time.Minute},
+	}
+	for _, tt := range tests {
+		got, err := ParseClipTime(tt.in)
+		if err != nil {
+			t.Errorf("ParseClipTime(%q): %v", tt.in, err)
+		} else if got != tt.want {
+			t.Errorf("ParseClipTime(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"1:75", "-5", "1:2:3:4", "abc"} {
+		if _, err := ParseClipTime(in); err == nil {
+			t.Errorf("ParseClipTime(%q): expected error", in)
+		}
+	}
+
+	if s := FormatClipTime(time.Hour + 500*time.Millisecond); s != "01:00:00.500" {
+		t.Errorf("FormatClipTime = %q", s)
+	}
+}
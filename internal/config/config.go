@@ -1,26 +1,190 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/cyber/whisper-transcribe/internal/audio"
+	"github.com/cyber/whisper-transcribe/internal/models"
+	"github.com/cyber/whisper-transcribe/internal/transcriber"
 	"github.com/spf13/viper"
 )
 
+// Whisper tasks.
+const (
+	TaskTranscribe = "transcribe"
+	// TaskTranslate translates the speech to English.
+	TaskTranslate = "translate"
+)
+
+// Caption modes decide whether YouTube's human-made captions are used.
+const (
+	// CaptionsWhisper always transcribes with whisper.
+	CaptionsWhisper = "whisper"
+	// CaptionsPrefer uses captions where they exist instead of whisper.
+	CaptionsPrefer = "prefer"
+	// CaptionsBoth transcribes with whisper and reports its word error
+	// rate against the captions.
+	CaptionsBoth = "both"
+)
+
+// CaptionsModel is the model name of transcripts taken from captions.
+const CaptionsModel = "captions"
+
+// MinChunkLength is the shortest chunk length. Whisper decodes 30 second
+// windows, and shorter chunks lose context at every cut.
+const MinChunkLength = time.Minute
+
 // Config holds the application configuration.
 type Config struct {
-	DefaultModel string `mapstructure:"default_model"`
-	OutputDir    string `mapstructure:"output_dir"`
-	Timestamps   bool   `mapstructure:"timestamps"`
+	DefaultModel  string   `mapstructure:"default_model"`
+	OutputDir     string   `mapstructure:"output_dir"`
+	Timestamps    bool     `mapstructure:"timestamps"`
+	OutputFormats []string `mapstructure:"output_formats"`
+	Workers       int      `mapstructure:"workers"`
+	MaxDownloads  int      `mapstructure:"max_downloads"`
+	// HistoryFile overrides the default history location.
+	HistoryFile string `mapstructure:"history_file"`
+	// CacheDir overrides the default transcription cache location.
+	CacheDir string `mapstructure:"cache_dir"`
+	// ModelMirror replaces the Hugging Face base URL for model downloads.
+	ModelMirror string `mapstructure:"model_mirror"`
+	// ModelRegistry is a YAML or JSON file listing additional models.
+	ModelRegistry string `mapstructure:"model_registry"`
+	// AutoTargetRTF is the real-time factor the auto model has to meet.
+	AutoTargetRTF float64 `mapstructure:"auto_target_rtf"`
+	// Language is the spoken language as a whisper code, or auto.
+	Language string `mapstructure:"language"`
+	// Task is transcribe or translate.
+	Task string `mapstructure:"task"`
+	// Profile names the whisper decoding profile used by default.
+	Profile string `mapstructure:"profile"`
+	// Profiles adds decoding profiles or adjusts the built-in ones.
+	Profiles map[string]transcriber.WhisperOverrides `mapstructure:"profiles"`
+	// Vocabulary is a YAML file of domain terms and misspelling fixes.
+	Vocabulary string `mapstructure:"vocabulary"`
+	// Diarize is stereo, tdrz, or empty to disable diarization.
+	Diarize string `mapstructure:"diarize"`
+	// Speakers maps speaker labels such as SPEAKER_00 to names.
+	Speakers map[string]string `mapstructure:"speakers"`
+	// Captions is whisper, prefer or both.
+	Captions string `mapstructure:"captions"`
+	// Audio selects the filters applied before transcription.
+	Audio audio.Options `mapstructure:"audio"`
+	// ChunkLength splits longer audio at pauses into chunks that are
+	// transcribed in parallel. Zero transcribes in one piece.
+	ChunkLength time.Duration `mapstructure:"chunk_length"`
+	// ChunkWorkers is how many chunks of a job are transcribed at once.
+	ChunkWorkers int `mapstructure:"chunk_workers"`
+	// KeepAudio saves downloaded audio as a WAV next to the transcript.
+	// Local files, cache hits and jobs using captions download nothing, so
+	// there is no audio to keep.
+	KeepAudio bool `mapstructure:"keep_audio"`
+}
+
+// DefaultProfile is the decoding profile used when none is selected.
+const DefaultProfile = "default"
+
+// builtinProfiles are decoding profiles available without configuration.
+var builtinProfiles = map[string]transcriber.WhisperOptions{
+	DefaultProfile: {MaxLen: 80},
+	// fast decodes greedily.
+	"fast": {MaxLen: 80, BeamSize: 1, BestOf: 1},
+	// accurate searches wider and falls back to sampling less readily.
+	"accurate": {MaxLen: 80, BeamSize: 8, BestOf: 8, EntropyThreshold: 2.8},
+	// podcast keeps long conversational segments readable and resists the
+	// repetition loops whisper falls into on long recordings.
+	"podcast": {MaxLen: 120, SplitOnWord: true, BeamSize: 5, EntropyThreshold: 2.6, LogprobThreshold: -0.8},
+}
+
+// ProfileNames returns the built-in and configured profile names, sorted.
+func (c *Config) ProfileNames() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for name := range builtinProfiles {
+		seen[name] = true
+		names = append(names, name)
+	}
+	for name := range c.Profiles {
+		if !seen[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WhisperProfile returns the decoding options of the named profile, or of
+// the configured one if name is empty. Configured profiles are applied on
+// top of the built-in profile of the same name, or of the default profile.
+func (c *Config) WhisperProfile(name string) (transcriber.WhisperOptions, error) {
+	if name == "" {
+		name = c.Profile
+	}
+	if name == "" {
+		name = DefaultProfile
+	}
+
+	base, builtin := builtinProfiles[name]
+	custom, configured := c.Profiles[name]
+	if !builtin && !configured {
+		return transcriber.WhisperOptions{}, fmt.Errorf("unknown profile %q (available: %s)", name, strings.Join(c.ProfileNames(), ", "))
+	}
+	if !builtin {
+		base = builtinProfiles[DefaultProfile]
+	}
+
+	opts := base.Merge(custom)
+	if err := opts.Validate(); err != nil {
+		return opts, fmt.Errorf("profile %s: %w", name, err)
+	}
+	return opts, nil
 }
 
 // TranscriptionConfig holds settings for a single transcription job.
 type TranscriptionConfig struct {
-	URL        string
-	LocalFile  string
-	Model      string
-	Timestamps bool
-	OutputDir  string
+	URL           string
+	LocalFile     string
+	Model         string
+	Timestamps    bool
+	OutputDir     string
+	OutputFormats []string
+	// Force re-transcribes even if a cached transcription exists.
+	Force bool
+	// Language is a whisper language code or auto.
+	Language string
+	// Task is TaskTranscribe or TaskTranslate.
+	Task string
+	// Whisper holds the decoding parameters of the selected profile.
+	Whisper transcriber.WhisperOptions
+	// Vocabulary is the path of a vocabulary file, if any.
+	Vocabulary string
+	// Diarize is transcriber.DiarizeStereo, transcriber.DiarizeTinydiarize
+	// or empty.
+	Diarize string
+	// RTTM is an external diarization file whose speaker turns are
+	// assigned to the segments.
+	RTTM string
+	// Speakers maps speaker labels to names.
+	Speakers map[string]string
+	// Captions is CaptionsWhisper, CaptionsPrefer or CaptionsBoth.
+	Captions string
+	// Audio selects the filters applied before transcription.
+	Audio audio.Options
+	// ChunkLength and ChunkWorkers control parallel transcription of
+	// long audio.
+	ChunkLength  time.Duration
+	ChunkWorkers int
+	// Start and End limit transcription to part of the media. Zero End
+	// means the end of the media.
+	Start time.Duration
+	End   time.Duration
+	// KeepAudio saves downloaded audio next to the transcript.
+	KeepAudio bool
 }
 
 // IsLocalFile returns true if transcribing from a local file.
@@ -36,12 +200,117 @@ func (c *TranscriptionConfig) GetSource() string {
 	return c.URL
 }
 
+// Translate returns true if the speech is translated to English.
+func (c *TranscriptionConfig) Translate() bool {
+	return c.Task == TaskTranslate
+}
+
+// EnglishOnlyFits reports whether the English-only variant of a model can
+// run the job.
+func (c *TranscriptionConfig) EnglishOnlyFits() bool {
+	return transcriber.EnglishOnlyFits(c.Language, c.Translate())
+}
+
+// Validate checks the language, task, diarization and captions modes
+// against each other and the model, the time range and the chunk length.
+func (c *TranscriptionConfig) Validate() error {
+	if c.Language != "" && !transcriber.IsLanguage(c.Language) {
+		return fmt.Errorf("unknown language %q (use a code such as en, de or es, or auto)", c.Language)
+	}
+	switch c.Task {
+	case "", TaskTranscribe, TaskTranslate:
+	default:
+		return fmt.Errorf("unknown task %q (use %s or %s)", c.Task, TaskTranscribe, TaskTranslate)
+	}
+	switch c.Diarize {
+	case "", transcriber.DiarizeStereo:
+	case transcriber.DiarizeTinydiarize:
+		if !strings.Contains(c.Model, "tdrz") {
+			return fmt.Errorf("tdrz diarization needs a tinydiarize model such as small.en-tdrz, not %s", c.Model)
+		}
+	default:
+		return fmt.Errorf("unknown diarization mode %q (use %s or %s)", c.Diarize, transcriber.DiarizeStereo, transcriber.DiarizeTinydiarize)
+	}
+	switch c.Captions {
+	case "", CaptionsWhisper, CaptionsPrefer, CaptionsBoth:
+	default:
+		return fmt.Errorf("unknown captions mode %q (use %s, %s or %s)", c.Captions, CaptionsWhisper, CaptionsPrefer, CaptionsBoth)
+	}
+	if c.Start < 0 || c.End < 0 {
+		return fmt.Errorf("start and end must not be negative")
+	}
+	if c.End > 0 && c.End <= c.Start {
+		return fmt.Errorf("end %s must be after start %s", FormatClipTime(c.End), FormatClipTime(c.Start))
+	}
+	if c.ChunkLength != 0 && c.ChunkLength < MinChunkLength {
+		return fmt.Errorf("chunk length %s is too short (minimum %s)", c.ChunkLength, MinChunkLength)
+	}
+	if transcriber.IsEnglishOnly(c.Model) {
+		if c.Translate() {
+			return fmt.Errorf("model %s is English-only and cannot translate; use a multilingual model", c.Model)
+		}
+		if c.Language != "" && c.Language != "en" && c.Language != transcriber.LanguageAuto {
+			return fmt.Errorf("model %s is English-only and cannot transcribe %q; use a multilingual model", c.Model, c.Language)
+		}
+	}
+	return nil
+}
+
+// TranscribeOptions returns the options for running whisper on this job.
+func (c *TranscriptionConfig) TranscribeOptions() transcriber.Options {
+	return transcriber.Options{
+		Model:     c.Model,
+		Language:  c.Language,
+		Translate: c.Translate(),
+		Diarize:   c.Diarize,
+		Whisper:   c.Whisper,
+	}
+}
+
+// Chunked returns true if long audio is split into chunks. Tinydiarize
+// only detects speaker changes, so its labels would restart in every chunk.
+func (c *TranscriptionConfig) Chunked() bool {
+	return c.ChunkLength > 0 && c.Diarize != transcriber.DiarizeTinydiarize
+}
+
+// Clipped returns true if only part of the media is transcribed.
+func (c *TranscriptionConfig) Clipped() bool {
+	return c.Start > 0 || c.End > 0
+}
+
+// AudioOptions returns the options for preparing this job's audio.
+func (c *TranscriptionConfig) AudioOptions() audio.Options {
+	opts := c.Audio
+	opts.Stereo = c.Diarize == transcriber.DiarizeStereo
+	if c.IsLocalFile() {
+		// Downloads are clipped by yt-dlp instead.
+		opts.Start, opts.End = c.Start, c.End
+	}
+	return opts
+}
+
+// Formats returns the requested output formats, defaulting to Markdown.
+func (c *TranscriptionConfig) Formats() []string {
+	if len(c.OutputFormats) == 0 {
+		return []string{"markdown"}
+	}
+	return c.OutputFormats
+}
+
 // Load reads configuration from file and environment.
 func Load(cfgFile string) (*Config, error) {
 	cfg := &Config{
-		DefaultModel: "base",
-		OutputDir:    getDefaultOutputDir(),
-		Timestamps:   false,
+		DefaultModel:  "base",
+		OutputDir:     getDefaultOutputDir(),
+		Timestamps:    false,
+		OutputFormats: []string{"markdown"},
+		Workers:       1,
+		MaxDownloads:  4,
+		AutoTargetRTF: models.DefaultTargetRTF,
+		Language:      transcriber.LanguageAuto,
+		Task:          TaskTranscribe,
+		Captions:      CaptionsWhisper,
+		ChunkWorkers:  4,
 	}
 
 	if cfgFile != "" {
@@ -80,7 +349,18 @@ func getDefaultOutputDir() string {
 	return filepath.Join(home, "transcripts")
 }
 
-// ModelOptions returns available Whisper model options.
+// ModelOptions returns available Whisper model options: auto selection
+// followed by every registered model, including .en and quantized variants.
 func ModelOptions() []string {
-	return []string{"tiny", "base", "small", "medium", "large"}
+	options := []string{models.Auto}
+	for _, m := range models.AvailableModels() {
+		options = append(options, m.Name)
+	}
+	return options
+}
+
+// LanguageOptions returns the selectable languages: auto detection
+// followed by every whisper language code.
+func LanguageOptions() []string {
+	return append([]string{transcriber.LanguageAuto}, transcriber.Languages...)
 }
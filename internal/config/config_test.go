@@ -0,0 +1,85 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cyber/whisper-transcribe/internal/transcriber"
+)
+
+func TestWhisperProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte(`
+profile: lecture
+profiles:
+  podcast:
+    threads: 16
+  accurate:
+    entropy_threshold: 0
+  lecture:
+    beam_size: 8
+    prompt: "Kubernetes, kubectl"
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	// A configured profile builds on the default one.
+	lecture, err := cfg.WhisperProfile("")
+	if err != nil {
+		t.Fatalf("WhisperProfile: %v", err)
+	}
+	if lecture.BeamSize != 8 || lecture.Prompt != "Kubernetes, kubectl" || lecture.MaxLen != 80 {
+		t.Errorf("lecture = %+v", lecture)
+	}
+
+	// One with a built-in name adjusts the built-in.
+	podcast, _ := cfg.WhisperProfile("podcast")
+	if podcast.Threads != 16 || !podcast.SplitOnWord || podcast.MaxLen != 120 {
+		t.Errorf("podcast = %+v", podcast)
+	}
+
+	// Zero overrides a built-in value rather than being ignored.
+	accurate, _ := cfg.WhisperProfile("accurate")
+	if accurate.EntropyThreshold != 0 || accurate.BeamSize != 8 {
+		t.Errorf("accurate = %+v", accurate)
+	}
+
+	if _, err := cfg.WhisperProfile("missing"); err == nil {
+		t.Error("expected error for unknown profile")
+	}
+}
+
+func TestChunking(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("chunk_length: 10m\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.ChunkLength != 10*time.Minute || cfg.ChunkWorkers != 4 {
+		t.Errorf("chunk_length = %v, chunk_workers = %d", cfg.ChunkLength, cfg.ChunkWorkers)
+	}
+
+	job := &TranscriptionConfig{Model: "base", ChunkLength: cfg.ChunkLength}
+	if !job.Chunked() {
+		t.Error("expected job to be chunked")
+	}
+	job.Diarize = transcriber.DiarizeTinydiarize
+	if job.Chunked() {
+		t.Error("tinydiarize jobs must not be chunked")
+	}
+
+	job = &TranscriptionConfig{Model: "base", ChunkLength: 30 * time.Second}
+	if err := job.Validate(); err == nil {
+		t.Error("expected error for a chunk length below the minimum")
+	}
+}
@@ -0,0 +1,83 @@
+package downloader
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cyber/whisper-transcribe/internal/procgroup"
+)
+
+// CaptionLanguage picks the subtitle track matching lang, a whisper
+// language code or "auto". For auto it prefers the video's own language,
+// then a sole track. It returns "" if no track fits.
+func CaptionLanguage(meta *Metadata, lang string) string {
+	if len(meta.Subtitles) == 0 {
+		return ""
+	}
+	if lang == "" || lang == "auto" {
+		if meta.Language != "" {
+			lang = meta.Language
+		} else if len(meta.Subtitles) == 1 {
+			return meta.Subtitles[0]
+		} else {
+			return ""
+		}
+	}
+
+	// Tracks are tagged e.g. "de" or "de-DE"; an exact match wins.
+	base, _, _ := strings.Cut(lang, "-")
+	match := ""
+	for _, track := range meta.Subtitles {
+		if track == lang {
+			return track
+		}
+		if t, _, _ := strings.Cut(track, "-"); t == base && match == "" {
+			match = track
+		}
+	}
+	return match
+}
+
+// FetchCaptions downloads the human-made subtitle track lang of a video as
+// WebVTT into dir, without downloading the video, and returns its path.
+func FetchCaptions(ctx context.Context, url, lang, dir string) (string, error) {
+	cmd := procgroup.Command(ctx, "yt-dlp",
+		"--write-subs",
+		"--sub-langs", lang,
+		"--sub-format", "vtt",
+		"--skip-download",
+		"-o", filepath.Join(dir, "captions.%(ext)s"),
+		url,
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("yt-dlp captions failed: %w: %s", err, lastLine(output))
+	}
+
+	// yt-dlp names the file captions.<lang>.vtt.
+	files, err := filepath.Glob(filepath.Join(dir, "captions.*.vtt"))
+	if err != nil || len(files) == 0 {
+		return "", fmt.Errorf("no %s captions downloaded", lang)
+	}
+	return files[0], nil
+}
+
+// ReadCaptions is FetchCaptions, returning the file's content.
+func ReadCaptions(ctx context.Context, url, lang, dir string) ([]byte, error) {
+	path, err := FetchCaptions(ctx, url, lang, dir)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read captions: %w", err)
+	}
+	return data, nil
+}
+
+func lastLine(output []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
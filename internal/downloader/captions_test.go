@@ -0,0 +1,27 @@
+package downloader
+
+import "testing"
+
+func TestCaptionLanguage(t *testing.T) {
+	tests := []struct {
+		subtitles []string
+		videoLang string
+		lang      string
+		want      string
+	}{
+		{[]string{"de", "en"}, "", "de", "de"},
+		{[]string{"de-DE", "en-US"}, "", "en", "en-US"},
+		{[]string{"es", "es-419"}, "", "es-419", "es-419"},
+		{[]string{"de", "en"}, "en", "auto", "en"},
+		{[]string{"fr"}, "", "auto", "fr"},
+		{[]string{"de", "en"}, "", "auto", ""},
+		{[]string{"de"}, "", "es", ""},
+		{nil, "en", "en", ""},
+	}
+	for _, tt := range tests {
+		meta := &Metadata{Subtitles: tt.subtitles, Language: tt.videoLang}
+		if got := CaptionLanguage(meta, tt.lang); got != tt.want {
+			t.Errorf("CaptionLanguage(%v, video %q, %q) = %q, want %q", tt.subtitles, tt.videoLang, tt.lang, got, tt.want)
+		}
+	}
+}
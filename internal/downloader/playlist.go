@@ -0,0 +1,187 @@
+package downloader
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cyber/whisper-transcribe/internal/procgroup"
+)
+
+// PlaylistOptions selects which entries of a playlist or channel to expand.
+type PlaylistOptions struct {
+	// Items is a yt-dlp item spec such as "1-10" or "1,3,5-7".
+	Items string
+	// DateAfter keeps only videos uploaded on or after this date (YYYYMMDD).
+	// The quick listing has no upload dates, so filtering by date fetches
+	// the full metadata of every video, which is much slower.
+	DateAfter string
+	// MaxCount limits the number of entries; zero means no limit.
+	MaxCount int
+}
+
+// Playlist holds the videos of an expanded playlist or channel.
+type Playlist struct {
+	ID      string
+	Title   string
+	URL     string
+	Entries []PlaylistEntry
+}
+
+// PlaylistEntry is a single video in a playlist.
+type PlaylistEntry struct {
+	Index      int
+	ID         string
+	Title      string
+	URL        string
+	UploadDate string
+}
+
+var (
+	dateRe       = regexp.MustCompile(`^\d{8}$`)
+	channelRe    = regexp.MustCompile(`youtube\.com/(@[^/?#]+|channel/[^/?#]+|c/[^/?#]+|user/[^/?#]+)/?$`)
+	channelTabRe = regexp.MustCompile(`youtube\.com/(@[^/?#]+|channel/[^/?#]+|c/[^/?#]+|user/[^/?#]+)/(videos|streams|shorts)/?$`)
+)
+
+// IsPlaylistURL reports whether the URL points at a playlist or channel
+// rather than a single video.
+func IsPlaylistURL(url string) bool {
+	url = strings.TrimSpace(url)
+	return strings.Contains(url, "youtube.com/playlist?") ||
+		channelRe.MatchString(url) ||
+		channelTabRe.MatchString(url)
+}
+
+// ValidateDate checks a YYYYMMDD date filter.
+func ValidateDate(date string) error {
+	if date != "" && !dateRe.MatchString(date) {
+		return fmt.Errorf("invalid date %q (expected YYYYMMDD)", date)
+	}
+	return nil
+}
+
+// ExpandPlaylist lists the videos of a playlist or channel without
+// downloading them.
+func ExpandPlaylist(ctx context.Context, url string, opts PlaylistOptions) (*Playlist, error) {
+	if err := ValidateDate(opts.DateAfter); err != nil {
+		return nil, err
+	}
+
+	url = strings.TrimSpace(url)
+	// A bare channel URL lists its tabs rather than its videos.
+	if channelRe.MatchString(url) {
+		url = strings.TrimSuffix(url, "/") + "/videos"
+	}
+
+	cmd := procgroup.Command(ctx, "yt-dlp", append(playlistArgs(opts), url)...)
+	output, err := cmd.Output()
+	// Without the quick listing, unavailable videos fail yt-dlp after the
+	// others have been listed.
+	if err != nil && (opts.DateAfter == "" || len(bytes.TrimSpace(output)) == 0) {
+		return nil, fmt.Errorf("yt-dlp playlist failed: %w", err)
+	}
+
+	playlist, err := parsePlaylist(output, opts)
+	if err != nil {
+		return nil, err
+	}
+	playlist.URL = url
+	return playlist, nil
+}
+
+// playlistArgs returns the yt-dlp arguments listing the entries opts
+// selects. Limits are applied by yt-dlp where possible, so that a large
+// channel isn't listed in full for a few videos.
+func playlistArgs(opts PlaylistOptions) []string {
+	args := []string{"--dump-json"}
+	if opts.DateAfter != "" {
+		args = append(args, "--dateafter", opts.DateAfter, "--ignore-errors")
+	} else {
+		args = append(args, "--flat-playlist")
+	}
+	switch {
+	case opts.Items != "":
+		args = append(args, "--playlist-items", opts.Items)
+	case opts.MaxCount > 0 && opts.DateAfter == "":
+		// With a date filter, the count applies to the matching videos.
+		args = append(args, "--playlist-end", strconv.Itoa(opts.MaxCount))
+	}
+	return args
+}
+
+// parsePlaylist reads the line-delimited JSON from --dump-json, with or
+// without --flat-playlist.
+func parsePlaylist(output []byte, opts PlaylistOptions) (*Playlist, error) {
+	playlist := &Playlist{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var data struct {
+			ID            string `json:"id"`
+			Title         string `json:"title"`
+			URL           string `json:"url"`
+			WebpageURL    string `json:"webpage_url"`
+			UploadDate    string `json:"upload_date"`
+			PlaylistID    string `json:"playlist_id"`
+			PlaylistTitle string `json:"playlist_title"`
+			PlaylistIndex int    `json:"playlist_index"`
+		}
+		if err := json.Unmarshal(line, &data); err != nil {
+			return nil, fmt.Errorf("parse playlist entry: %w", err)
+		}
+
+		if playlist.Title == "" {
+			playlist.ID = data.PlaylistID
+			playlist.Title = data.PlaylistTitle
+		}
+
+		if opts.DateAfter != "" && data.UploadDate != "" && data.UploadDate < opts.DateAfter {
+			continue
+		}
+
+		// Full metadata has the media stream in url and the video's page
+		// in webpage_url.
+		entryURL := data.WebpageURL
+		if entryURL == "" {
+			entryURL = data.URL
+		}
+		if !strings.HasPrefix(entryURL, "http") {
+			entryURL = "https://www.youtube.com/watch?v=" + data.ID
+		}
+
+		playlist.Entries = append(playlist.Entries, PlaylistEntry{
+			Index:      data.PlaylistIndex,
+			ID:         data.ID,
+			Title:      data.Title,
+			URL:        entryURL,
+			UploadDate: data.UploadDate,
+		})
+
+		if opts.MaxCount > 0 && len(playlist.Entries) >= opts.MaxCount {
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read playlist: %w", err)
+	}
+
+	if playlist.Title == "" {
+		playlist.Title = playlist.ID
+	}
+	if len(playlist.Entries) == 0 {
+		return nil, fmt.Errorf("playlist has no matching videos")
+	}
+
+	return playlist, nil
+}
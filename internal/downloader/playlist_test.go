@@ -0,0 +1,91 @@
+package downloader
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsPlaylistURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://www.youtube.com/playlist?list=PL123", true},
+		{"https://www.youtube.com/@SomeChannel", true},
+		{"https://www.youtube.com/@SomeChannel/videos", true},
+		{"https://www.youtube.com/channel/UCabc/streams", true},
+		{"https://www.youtube.com/c/Legacy/", true},
+		{"https://www.youtube.com/watch?v=abc&list=PL123", false},
+		{"https://www.youtube.com/shorts/abc", false},
+		{"https://youtu.be/abc", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsPlaylistURL(tt.url); got != tt.want {
+			t.Errorf("IsPlaylistURL(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+		if err := ValidateURL(tt.url); err != nil {
+			t.Errorf("ValidateURL(%q) failed: %v", tt.url, err)
+		}
+	}
+}
+
+const flatPlaylist = `{"_type": "url", "id": "vid1", "url": "https://www.youtube.com/watch?v=vid1", "title": "First", "upload_date": "20240301", "playlist_id": "PL1", "playlist_title": "Conference 2024", "playlist_index": 1}
+{"_type": "url", "id": "vid2", "url": "vid2", "title": "Second", "upload_date": "20230101", "playlist_id": "PL1", "playlist_title": "Conference 2024", "playlist_index": 2}
+{"_type": "url", "id": "vid3", "url": "https://www.youtube.com/watch?v=vid3", "title": "Third", "upload_date": null, "playlist_id": "PL1", "playlist_title": "Conference 2024", "playlist_index": 3}
+{"_type": "url", "id": "vid4", "url": "https://www.youtube.com/watch?v=vid4", "title": "Fourth", "upload_date": "20240501", "playlist_id": "PL1", "playlist_title": "Conference 2024", "playlist_index": 4}
+`
+
+func TestParsePlaylist(t *testing.T) {
+	playlist, err := parsePlaylist([]byte(flatPlaylist), PlaylistOptions{})
+	if err != nil {
+		t.Fatalf("parsePlaylist failed: %v", err)
+	}
+	if playlist.Title != "Conference 2024" || len(playlist.Entries) != 4 {
+		t.Fatalf("unexpected playlist: %+v", playlist)
+	}
+	if got := playlist.Entries[1].URL; got != "https://www.youtube.com/watch?v=vid2" {
+		t.Errorf("expected URL built from ID, got %q", got)
+	}
+
+	filtered, err := parsePlaylist([]byte(flatPlaylist), PlaylistOptions{DateAfter: "20240101", MaxCount: 2})
+	if err != nil {
+		t.Fatalf("parsePlaylist failed: %v", err)
+	}
+	if len(filtered.Entries) != 2 || filtered.Entries[0].ID != "vid1" || filtered.Entries[1].ID != "vid3" {
+		t.Errorf("unexpected filtered entries: %+v", filtered.Entries)
+	}
+
+	if _, err := parsePlaylist(nil, PlaylistOptions{}); err == nil {
+		t.Error("expected error for empty playlist")
+	}
+}
+
+func TestPlaylistArgs(t *testing.T) {
+	tests := []struct {
+		opts PlaylistOptions
+		want string
+	}{
+		{PlaylistOptions{}, "--dump-json --flat-playlist"},
+		{PlaylistOptions{MaxCount: 10}, "--dump-json --flat-playlist --playlist-end 10"},
+		{PlaylistOptions{Items: "1-5", MaxCount: 3}, "--dump-json --flat-playlist --playlist-items 1-5"},
+		{PlaylistOptions{DateAfter: "20240101", MaxCount: 10}, "--dump-json --dateafter 20240101 --ignore-errors"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(playlistArgs(tt.opts), " "); got != tt.want {
+			t.Errorf("playlistArgs(%+v) = %q, want %q", tt.opts, got, tt.want)
+		}
+	}
+}
+
+func TestParseFullPlaylist(t *testing.T) {
+	// Entries with full metadata have the media stream in url.
+	full := `{"id": "vid1", "url": "https://rr1.googlevideo.com/videoplayback?id=1", "webpage_url": "https://www.youtube.com/watch?v=vid1", "title": "First", "upload_date": "20240301", "playlist_title": "Talks", "playlist_index": 1}`
+	playlist, err := parsePlaylist([]byte(full), PlaylistOptions{DateAfter: "20240101"})
+	if err != nil {
+		t.Fatalf("parsePlaylist failed: %v", err)
+	}
+	if got := playlist.Entries[0].URL; got != "https://www.youtube.com/watch?v=vid1" {
+		t.Errorf("expected the video page URL, got %q", got)
+	}
+}
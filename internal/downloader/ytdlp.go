@@ -5,12 +5,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
-	"os/exec"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/cyber/whisper-transcribe/internal/procgroup"
 )
 
 // Metadata holds video information from YouTube.
@@ -23,14 +25,57 @@ type Metadata struct {
 	UploadDate  string
 	Description string
 	VideoID     string
+	// Chapters are the video's chapters in order, if it has any.
+	Chapters []Chapter `json:",omitempty"`
+	// Language is the video's spoken language as reported by YouTube,
+	// if known.
+	Language string `json:",omitempty"`
+	// Subtitles lists the languages of human-made subtitles.
+	Subtitles []string `json:",omitempty"`
+}
+
+// Chapter is a titled section of a video.
+type Chapter struct {
+	Title string
+	Start time.Duration
+	End   time.Duration
 }
 
 // ProgressFunc is called with download progress (0.0 to 1.0).
 type ProgressFunc func(progress float64)
 
+// Options control how audio is extracted.
+type Options struct {
+	// Stereo keeps both channels instead of mixing down to mono, which
+	// stereo diarization needs.
+	Stereo bool
+	// Start and End download only part of the video. Zero End means the
+	// end of the video.
+	Start time.Duration
+	End   time.Duration
+}
+
+// sectionArgs returns the yt-dlp arguments for downloading only the time
+// range of opts, if any. The cuts are made exactly rather than at the
+// nearest keyframes, since timestamps are shifted by the start.
+func (o Options) sectionArgs() []string {
+	if o.Start <= 0 && o.End <= 0 {
+		return nil
+	}
+	end := "inf"
+	if o.End > 0 {
+		end = formatSeconds(o.End)
+	}
+	return []string{"--download-sections", "*" + formatSeconds(o.Start) + "-" + end, "--force-keyframes-at-cuts"}
+}
+
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64)
+}
+
 // FetchMetadata retrieves video information without downloading.
 func FetchMetadata(ctx context.Context, url string) (*Metadata, error) {
-	cmd := exec.CommandContext(ctx, "yt-dlp",
+	cmd := procgroup.Command(ctx, "yt-dlp",
 		"--dump-json",
 		"--no-download",
 		url,
@@ -41,6 +86,11 @@ func FetchMetadata(ctx context.Context, url string) (*Metadata, error) {
 		return nil, fmt.Errorf("yt-dlp metadata failed: %w", err)
 	}
 
+	return parseMetadata(output)
+}
+
+// parseMetadata converts yt-dlp --dump-json output to Metadata.
+func parseMetadata(output []byte) (*Metadata, error) {
 	var data struct {
 		Title       string `json:"title"`
 		Channel     string `json:"channel"`
@@ -49,13 +99,22 @@ func FetchMetadata(ctx context.Context, url string) (*Metadata, error) {
 		UploadDate  string `json:"upload_date"`
 		Description string `json:"description"`
 		ID          string `json:"id"`
+		Language    string `json:"language"`
+		// Subtitles holds human-made subtitles only; automatic captions
+		// are listed separately.
+		Subtitles map[string]json.RawMessage `json:"subtitles"`
+		Chapters  []struct {
+			Title     string  `json:"title"`
+			StartTime float64 `json:"start_time"`
+			EndTime   float64 `json:"end_time"`
+		} `json:"chapters"`
 	}
 
 	if err := json.Unmarshal(output, &data); err != nil {
 		return nil, fmt.Errorf("parse metadata: %w", err)
 	}
 
-	return &Metadata{
+	meta := &Metadata{
 		Title:       data.Title,
 		Channel:     data.Channel,
 		ChannelURL:  data.ChannelURL,
@@ -64,28 +123,53 @@ func FetchMetadata(ctx context.Context, url string) (*Metadata, error) {
 		UploadDate:  data.UploadDate,
 		Description: data.Description,
 		VideoID:     data.ID,
-	}, nil
+		Language:    data.Language,
+	}
+	for lang := range data.Subtitles {
+		// Live chat replays are listed as a subtitle track.
+		if lang != "live_chat" {
+			meta.Subtitles = append(meta.Subtitles, lang)
+		}
+	}
+	sort.Strings(meta.Subtitles)
+	for _, c := range data.Chapters {
+		meta.Chapters = append(meta.Chapters, Chapter{
+			Title: strings.TrimSpace(c.Title),
+			Start: seconds(c.StartTime),
+			End:   seconds(c.EndTime),
+		})
+	}
+	sort.SliceStable(meta.Chapters, func(i, j int) bool {
+		return meta.Chapters[i].Start < meta.Chapters[j].Start
+	})
+	return meta, nil
 }
 
-// Download extracts audio from the video and converts to WAV format.
-func Download(ctx context.Context, url string, onProgress ProgressFunc) (string, error) {
-	tmpDir := filepath.Join(os.TempDir(), "whisper-transcribe")
-	if err := os.MkdirAll(tmpDir, 0755); err != nil {
-		return "", fmt.Errorf("create temp dir: %w", err)
-	}
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second)).Round(time.Millisecond)
+}
 
-	outputTemplate := filepath.Join(tmpDir, "%(id)s.%(ext)s")
+// Download extracts audio from the video into dir and converts it to WAV
+// format. dir should belong to the job, since the audio is found there if
+// yt-dlp doesn't name it.
+func Download(ctx context.Context, url, dir string, opts Options, onProgress ProgressFunc) (string, error) {
+	outputTemplate := filepath.Join(dir, "%(id)s.%(ext)s")
+	channels := "1"
+	if opts.Stereo {
+		channels = "2"
+	}
 
-	cmd := exec.CommandContext(ctx, "yt-dlp",
+	args := []string{
 		"--extract-audio",
 		"--audio-format", "wav",
 		"--audio-quality", "0",
-		"--postprocessor-args", "ffmpeg:-ar 16000 -ac 1",
+		"--postprocessor-args", "ffmpeg:-ar 16000 -ac " + channels,
 		"--newline",
 		"--progress",
 		"-o", outputTemplate,
-		url,
-	)
+	}
+	args = append(args, opts.sectionArgs()...)
+	cmd := procgroup.Command(ctx, "yt-dlp", append(args, url)...)
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
@@ -136,18 +220,18 @@ func Download(ctx context.Context, url string, onProgress ProgressFunc) (string,
 	}
 
 	if audioPath == "" {
-		files, _ := filepath.Glob(filepath.Join(tmpDir, "*.wav"))
-		if len(files) > 0 {
-			audioPath = files[len(files)-1]
-		} else {
+		files, _ := filepath.Glob(filepath.Join(dir, "*.wav"))
+		if len(files) != 1 {
 			return "", fmt.Errorf("no audio file produced")
 		}
+		audioPath = files[0]
 	}
 
 	return audioPath, nil
 }
 
-// ValidateURL checks if the URL is a valid YouTube URL.
+// ValidateURL checks if the URL is a valid YouTube video, playlist or
+// channel URL.
 func ValidateURL(url string) error {
 	url = strings.TrimSpace(url)
 	if url == "" {
@@ -155,7 +239,8 @@ func ValidateURL(url string) error {
 	}
 	if !strings.Contains(url, "youtube.com/watch") &&
 		!strings.Contains(url, "youtu.be/") &&
-		!strings.Contains(url, "youtube.com/shorts/") {
+		!strings.Contains(url, "youtube.com/shorts/") &&
+		!IsPlaylistURL(url) {
 		return fmt.Errorf("invalid YouTube URL")
 	}
 	return nil
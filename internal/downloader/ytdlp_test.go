@@ -0,0 +1,56 @@
+package downloader
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseMetadata(t *testing.T) {
+	output := `{"id": "abc", "title": "Lecture", "duration": 3600, "language": "en",
+		"subtitles": {"live_chat": [], "en": [], "de": []}, "chapters": [
+		{"start_time": 600.5, "end_time": 3600, "title": " Part Two "},
+		{"start_time": 0, "end_time": 600.5, "title": "Intro"}
+	]}`
+
+	meta, err := parseMetadata([]byte(output))
+	if err != nil {
+		t.Fatalf("parseMetadata: %v", err)
+	}
+	if meta.VideoID != "abc" || meta.Duration != "1:00:00" || meta.Language != "en" {
+		t.Errorf("meta = %+v", meta)
+	}
+	if len(meta.Subtitles) != 2 || meta.Subtitles[0] != "de" || meta.Subtitles[1] != "en" {
+		t.Errorf("subtitles = %v", meta.Subtitles)
+	}
+	if len(meta.Chapters) != 2 {
+		t.Fatalf("chapters = %+v", meta.Chapters)
+	}
+	want := Chapter{Title: "Part Two", Start: 600500 * time.Millisecond, End: time.Hour}
+	if meta.Chapters[0].Title != "Intro" || meta.Chapters[1] != want {
+		t.Errorf("chapters = %+v", meta.Chapters)
+	}
+
+	if meta, _ := parseMetadata([]byte(`{"id": "x"}`)); meta.Chapters != nil {
+		t.Errorf("chapters without chapters = %+v", meta.Chapters)
+	}
+}
+
+func TestSectionArgs(t *testing.T) {
+	tests := []struct {
+		opts Options
+		want string
+	}{
+		{Options{Start: 10 * time.Minute, End: 20*time.Minute + 500*time.Millisecond}, "*600-1200.5"},
+		{Options{Start: time.Hour}, "*3600-inf"},
+		{Options{End: 90 * time.Second}, "*0-90"},
+	}
+	for _, tt := range tests {
+		args := tt.opts.sectionArgs()
+		if len(args) != 3 || args[0] != "--download-sections" || args[1] != tt.want || args[2] != "--force-keyframes-at-cuts" {
+			t.Errorf("sectionArgs(%+v) = %v, want %s", tt.opts, args, tt.want)
+		}
+	}
+	if args := (Options{Stereo: true}).sectionArgs(); args != nil {
+		t.Errorf("expected no sections without a range, got %v", args)
+	}
+}
@@ -0,0 +1,137 @@
+package formatter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/cyber/whisper-transcribe/internal/config"
+	"github.com/cyber/whisper-transcribe/internal/downloader"
+	"github.com/cyber/whisper-transcribe/internal/transcriber"
+)
+
+// renderChapters renders the transcript with a ### section per chapter and
+// returns it together with a table of contents linking to the sections.
+// Without chapters the transcript is rendered as a whole and the table of
+// contents is empty. title is the document's h1, whose anchor the chapter
+// anchors must not collide with.
+func renderChapters(chapters []downloader.Chapter, segments []transcriber.Segment, cfg *config.TranscriptionConfig, title string) (content, toc string) {
+	if len(chapters) == 0 {
+		return renderSegments(segments, cfg), ""
+	}
+
+	anchors := newAnchorSet(title, "Contents", "Transcription")
+	buckets := bucketByChapter(chapters, segments)
+
+	var body, contents strings.Builder
+	for i, ch := range chapters {
+		heading := ch.Title
+		if heading == "" {
+			heading = fmt.Sprintf("Chapter %d", i+1)
+		}
+		anchor := anchors.add(heading)
+
+		item := fmt.Sprintf("[%s](#%s) (%s)", escapeInline(heading), anchor, formatChapterTime(ch.Start))
+		contents.WriteString(wrapListItem("- ", item, maxLineLength))
+		contents.WriteString("\n")
+
+		body.WriteString("### " + escapeInline(heading) + "\n\n")
+		if text := renderSegments(buckets[i], cfg); text != "" {
+			body.WriteString(text)
+			body.WriteString("\n\n")
+		}
+	}
+
+	return strings.TrimSpace(body.String()), strings.TrimSpace(contents.String())
+}
+
+// inlineEscaper escapes the characters that start inline Markdown syntax
+// or close a heading.
+var inlineEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`,
+	"[", `\[`, "]", `\]`, "<", `\<`, "#", `\#`,
+)
+
+// leadingBlockRe matches text that would start a list or quote.
+var leadingBlockRe = regexp.MustCompile(`^(\d+)([.)])|^([-+>])`)
+
+// escapeInline escapes a chapter title so that it renders literally in a
+// heading or as link text.
+func escapeInline(s string) string {
+	s = inlineEscaper.Replace(strings.TrimSpace(s))
+	return leadingBlockRe.ReplaceAllString(s, `$1\$2$3`)
+}
+
+// clipChapters returns the chapters that overlap the job's time range.
+func clipChapters(chapters []downloader.Chapter, cfg *config.TranscriptionConfig) []downloader.Chapter {
+	if !cfg.Clipped() {
+		return chapters
+	}
+	var out []downloader.Chapter
+	for _, ch := range chapters {
+		if ch.End <= cfg.Start || (cfg.End > 0 && ch.Start >= cfg.End) {
+			continue
+		}
+		out = append(out, ch)
+	}
+	return out
+}
+
+// bucketByChapter assigns each segment to the last chapter starting at or
+// before the segment. Segments before the first chapter go to the first.
+func bucketByChapter(chapters []downloader.Chapter, segments []transcriber.Segment) [][]transcriber.Segment {
+	buckets := make([][]transcriber.Segment, len(chapters))
+	ch := 0
+	for _, seg := range segments {
+		start := seg.StartTime()
+		for ch+1 < len(chapters) && chapters[ch+1].Start <= start {
+			ch++
+		}
+		buckets[ch] = append(buckets[ch], seg)
+	}
+	return buckets
+}
+
+// formatChapterTime formats a chapter start like the video player does,
+// as M:SS or H:MM:SS.
+func formatChapterTime(d time.Duration) string {
+	total := int(d.Seconds())
+	h, m, s := total/3600, total%3600/60, total%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%d:%02d", m, s)
+}
+
+// anchorSet generates heading anchors the way GitHub and markdownlint do,
+// numbering repeated headings.
+type anchorSet map[string]int
+
+func newAnchorSet(headings ...string) anchorSet {
+	a := anchorSet{}
+	for _, h := range headings {
+		a.add(h)
+	}
+	return a
+}
+
+func (a anchorSet) add(heading string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r), r == '-', r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('-')
+		}
+	}
+	anchor := b.String()
+	n := a[anchor]
+	a[anchor] = n + 1
+	if n > 0 {
+		return fmt.Sprintf("%s-%d", anchor, n)
+	}
+	return anchor
+}
@@ -0,0 +1,126 @@
+package formatter
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cyber/whisper-transcribe/internal/config"
+	"github.com/cyber/whisper-transcribe/internal/downloader"
+	"github.com/cyber/whisper-transcribe/internal/transcriber"
+)
+
+func TestGenerateMarkdownChapters(t *testing.T) {
+	meta := &downloader.Metadata{
+		Title:    "Lecture",
+		Channel:  "University",
+		Duration: "1:10:00",
+		Chapters: []downloader.Chapter{
+			{Title: "Introduction", Start: 0},
+			{Title: "Transcription", Start: 30 * time.Second},
+			{Title: "Q&A [live]", Start: time.Hour + 5*time.Minute},
+		},
+	}
+	segments := []transcriber.Segment{
+		{Start: "00:00:01.000", End: "00:00:05.000", Text: "Welcome."},
+		{Start: "00:00:45.000", End: "00:00:50.000", Text: "Speech to text."},
+		{Start: "01:06:00.000", End: "01:06:03.000", Text: "Any questions?"},
+	}
+	cfg := &config.TranscriptionConfig{
+		URL:       "https://www.youtube.com/watch?v=chap123",
+		Model:     "base",
+		OutputDir: t.TempDir(),
+	}
+
+	outputPath, err := GenerateMarkdown(meta, segments, cfg)
+	if err != nil {
+		t.Fatalf("GenerateMarkdown failed: %v", err)
+	}
+	data, _ := os.ReadFile(outputPath)
+	content := string(data)
+
+	for _, want := range []string{
+		"## Contents\n\n- [Introduction](#introduction) (0:00)\n",
+		// Anchors don't collide with the document's own headings.
+		"- [Transcription](#transcription-1) (0:30)\n",
+		`- [Q&A \[live\]](#qa-live) (1:05:00)`,
+		"### Introduction\n\nWelcome.\n\n### Transcription\n\nSpeech to text.\n\n",
+		`### Q&A \[live\]` + "\n\nAny questions?\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("output missing %q:\n%s", want, content)
+		}
+	}
+}
+
+func TestBucketByChapter(t *testing.T) {
+	chapters := []downloader.Chapter{{Start: 10 * time.Second}, {Start: 20 * time.Second}, {Start: 30 * time.Second}}
+	segments := []transcriber.Segment{
+		{Start: "00:00:02.000"}, // before the first chapter
+		{Start: "00:00:20.000"},
+		{Start: "00:00:45.000"},
+	}
+	buckets := bucketByChapter(chapters, segments)
+	if len(buckets[0]) != 1 || len(buckets[1]) != 1 || len(buckets[2]) != 1 {
+		t.Errorf("buckets = %+v", buckets)
+	}
+}
+
+func TestEscapeInline(t *testing.T) {
+	tests := map[string]string{
+		"1. Intro":           `1\. Intro`,
+		"- Q&A":              `\- Q&A`,
+		"Part *2* #live":     `Part \*2\* \#live`,
+		"snake_case [draft]": `snake\_case \[draft\]`,
+	}
+	for in, want := range tests {
+		if got := escapeInline(in); got != want {
+			t.Errorf("escapeInline(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestGenerateMarkdownClip(t *testing.T) {
+	meta := &downloader.Metadata{
+		Title:    "Livestream",
+		Channel:  "Streamer",
+		Duration: "3:00:00",
+		Chapters: []downloader.Chapter{
+			{Title: "Setup", Start: 0, End: time.Hour},
+			{Title: "Talk", Start: time.Hour, End: 2 * time.Hour},
+			{Title: "Outro", Start: 2 * time.Hour, End: 3 * time.Hour},
+		},
+	}
+	segments := []transcriber.Segment{
+		{Start: "01:10:00.000", End: "01:10:04.000", Text: "Where were we?"},
+	}
+	cfg := &config.TranscriptionConfig{
+		URL:       "https://www.youtube.com/watch?v=clip123",
+		Model:     "base",
+		OutputDir: t.TempDir(),
+		Start:     time.Hour + 10*time.Minute,
+		End:       time.Hour + 20*time.Minute,
+	}
+
+	outputPath, err := GenerateMarkdown(meta, segments, cfg)
+	if err != nil {
+		t.Fatalf("GenerateMarkdown failed: %v", err)
+	}
+	data, _ := os.ReadFile(outputPath)
+	content := string(data)
+
+	for _, want := range []string{
+		`clip_start: "01:10:00"`,
+		`clip_end: "01:20:00"`,
+		"- [Talk](#talk) (1:00:00)\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("output missing %q:\n%s", want, content)
+		}
+	}
+	// Only chapters within the clip are listed.
+	if strings.Contains(content, "Setup") || strings.Contains(content, "Outro") {
+		t.Errorf("output lists chapters outside the clip:\n%s", content)
+	}
+}
@@ -0,0 +1,135 @@
+package formatter
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cyber/whisper-transcribe/internal/transcriber"
+	"gopkg.in/yaml.v3"
+)
+
+// Vocabulary is a list of domain terms and known misspellings, read from a
+// YAML file:
+//
+//	terms: [Kubernetes, kubectl, etcd]
+//	replace:
+//	  cube control: kubectl
+//	  cube cuddle: kubectl
+type Vocabulary struct {
+	// Terms are passed to whisper as the initial prompt so that it prefers
+	// their spelling.
+	Terms []string `yaml:"terms"`
+	// Replace maps misspellings to their correction. Matching ignores case
+	// and only replaces whole words.
+	Replace map[string]string `yaml:"replace"`
+
+	rules []glossaryRule
+}
+
+type glossaryRule struct {
+	re          *regexp.Regexp
+	replacement string
+}
+
+// LoadVocabulary reads a vocabulary file.
+func LoadVocabulary(path string) (*Vocabulary, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read vocabulary: %w", err)
+	}
+
+	var v Vocabulary
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("parse vocabulary %s: %w", path, err)
+	}
+	if err := v.compile(); err != nil {
+		return nil, fmt.Errorf("vocabulary %s: %w", path, err)
+	}
+	return &v, nil
+}
+
+// compile builds the replacement rules, longest misspelling first so that
+// "cube control plane" wins over "cube control".
+func (v *Vocabulary) compile() error {
+	from := make([]string, 0, len(v.Replace))
+	for k := range v.Replace {
+		if strings.TrimSpace(k) == "" {
+			return fmt.Errorf("empty misspelling for %q", v.Replace[k])
+		}
+		from = append(from, k)
+	}
+	sort.Slice(from, func(i, j int) bool {
+		if len(from[i]) != len(from[j]) {
+			return len(from[i]) > len(from[j])
+		}
+		return from[i] < from[j]
+	})
+
+	v.rules = nil
+	for _, k := range from {
+		// Whitespace inside a phrase matches any run of whitespace, and
+		// \b only applies where the phrase starts or ends with a word
+		// character.
+		words := strings.Fields(k)
+		for i, w := range words {
+			words[i] = regexp.QuoteMeta(w)
+		}
+		pattern := strings.Join(words, `\s+`)
+		phrase := strings.TrimSpace(k)
+		if isWordChar(phrase[0]) {
+			pattern = `\b` + pattern
+		}
+		if isWordChar(phrase[len(phrase)-1]) {
+			pattern += `\b`
+		}
+		v.rules = append(v.rules, glossaryRule{
+			re:          regexp.MustCompile(`(?i)` + pattern),
+			replacement: v.Replace[k],
+		})
+	}
+	return nil
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// Prompt returns the initial prompt for whisper: base, if any, followed by
+// the vocabulary terms.
+func (v *Vocabulary) Prompt(base string) string {
+	if v == nil || len(v.Terms) == 0 {
+		return base
+	}
+	terms := strings.Join(v.Terms, ", ")
+	if base == "" {
+		return terms
+	}
+	return base + " " + terms
+}
+
+// Apply returns a copy of segments with every misspelling corrected and the
+// number of replacements made. Token data is left as whisper produced it.
+func (v *Vocabulary) Apply(segments []transcriber.Segment) ([]transcriber.Segment, int) {
+	if v == nil || len(v.Replace) == 0 {
+		return segments, 0
+	}
+	if v.rules == nil && v.compile() != nil {
+		return segments, 0
+	}
+
+	out := make([]transcriber.Segment, len(segments))
+	count := 0
+	for i, seg := range segments {
+		for _, r := range v.rules {
+			seg.Text = r.re.ReplaceAllStringFunc(seg.Text, func(string) string {
+				count++
+				return r.replacement
+			})
+		}
+		out[i] = seg
+	}
+	return out, count
+}
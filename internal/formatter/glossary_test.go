@@ -0,0 +1,57 @@
+package formatter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cyber/whisper-transcribe/internal/transcriber"
+)
+
+func TestVocabulary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vocab.yaml")
+	err := os.WriteFile(path, []byte(`
+terms: [Kubernetes, kubectl]
+replace:
+  cube control: kubectl
+  cube control plane: control plane
+  e.t.c.d: etcd
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := LoadVocabulary(path)
+	if err != nil {
+		t.Fatalf("LoadVocabulary: %v", err)
+	}
+
+	if got := v.Prompt(""); got != "Kubernetes, kubectl" {
+		t.Errorf("Prompt = %q", got)
+	}
+	if got := v.Prompt("A talk."); got != "A talk. Kubernetes, kubectl" {
+		t.Errorf("Prompt with base = %q", got)
+	}
+
+	segments := []transcriber.Segment{
+		{Text: "Run Cube  Control get pods."},
+		{Text: "The cube control plane stores state in e.t.c.d."},
+		{Text: "Cubecontrol is not a phrase."},
+	}
+	got, n := v.Apply(segments)
+	want := []string{
+		"Run kubectl get pods.",
+		"The control plane stores state in etcd.",
+		"Cubecontrol is not a phrase.",
+	}
+	for i := range want {
+		if got[i].Text != want[i] {
+			t.Errorf("segment %d = %q, want %q", i, got[i].Text, want[i])
+		}
+	}
+	if n != 3 {
+		t.Errorf("replacements = %d, want 3", n)
+	}
+	if segments[0].Text != "Run Cube  Control get pods." {
+		t.Error("Apply modified its input")
+	}
+}
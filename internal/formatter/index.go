@@ -0,0 +1,77 @@
+package formatter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// IndexEntry is one transcript listed in a playlist index.
+type IndexEntry struct {
+	Title string
+	URL   string
+	// Path is the transcript file; empty if the video failed.
+	Path string
+}
+
+// PlaylistDir returns the per-playlist subdirectory of outputDir.
+func PlaylistDir(outputDir, playlistTitle string) string {
+	dir := slugify(playlistTitle)
+	if dir == "" {
+		dir = "playlist"
+	}
+	return filepath.Join(outputDir, dir)
+}
+
+// GeneratePlaylistIndex writes index.md into dir, linking every transcript
+// of a playlist. Links are relative so the directory can be moved.
+func GeneratePlaylistIndex(title, source, dir string, entries []IndexEntry) (string, error) {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "---\ntitle: \"%s\"\nsource: \"%s\"\ntranscribed: \"%s\"\nvideos: %d\n---\n\n",
+		sanitizeTitle(title), source, time.Now().Format("2006-01-02"), len(entries))
+	fmt.Fprintf(&b, "# %s\n\n", sanitizeTitle(title))
+	b.WriteString(wrapBlockquote(fmt.Sprintf("Transcripts of [this playlist](%s)", source), maxLineLength))
+	b.WriteString("\n\n## Videos\n\n")
+
+	for i, e := range entries {
+		var item string
+		if e.Path != "" {
+			rel, err := filepath.Rel(dir, e.Path)
+			if err != nil {
+				rel = e.Path
+			}
+			item = fmt.Sprintf("[%s](%s)", escapeLinkText(e.Title), filepath.ToSlash(rel))
+		} else {
+			item = fmt.Sprintf("%s (not transcribed)", escapeLinkText(e.Title))
+		}
+		b.WriteString(wrapListItem(fmt.Sprintf("%d. ", i+1), item, maxLineLength))
+		b.WriteString("\n")
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("create output dir: %w", err)
+	}
+
+	path := filepath.Join(dir, "index.md")
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return "", fmt.Errorf("write file: %w", err)
+	}
+	return path, nil
+}
+
+func escapeLinkText(s string) string {
+	s = strings.ReplaceAll(s, "[", `\[`)
+	s = strings.ReplaceAll(s, "]", `\]`)
+	return strings.TrimSpace(s)
+}
+
+// wrapListItem wraps a list item, indenting continuation lines to the
+// marker width so they stay part of the item.
+func wrapListItem(marker, text string, maxLen int) string {
+	indent := strings.Repeat(" ", len(marker))
+	wrapped := wrapText(text, maxLen-len(marker))
+	return marker + strings.ReplaceAll(wrapped, "\n", "\n"+indent)
+}
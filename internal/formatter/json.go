@@ -0,0 +1,134 @@
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cyber/whisper-transcribe/internal/config"
+	"github.com/cyber/whisper-transcribe/internal/downloader"
+	"github.com/cyber/whisper-transcribe/internal/transcriber"
+)
+
+// FormatJSON is the name of the structured JSON output format.
+const FormatJSON = "json"
+
+func init() {
+	Register(jsonWriter{})
+}
+
+// JSONTranscript is the document written by the JSON output format.
+type JSONTranscript struct {
+	Source      string        `json:"source"`
+	Transcribed string        `json:"transcribed"`
+	Language    string        `json:"language,omitempty"`
+	Task        string        `json:"task,omitempty"`
+	Clip        *JSONClip     `json:"clip,omitempty"`
+	Metadata    JSONMetadata  `json:"metadata"`
+	Stats       JSONStats     `json:"stats"`
+	Segments    []JSONSegment `json:"segments"`
+}
+
+// JSONClip is the transcribed time range in seconds, if only part of the
+// media was transcribed. End is omitted for the end of the media.
+type JSONClip struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end,omitempty"`
+}
+
+// JSONMetadata holds video metadata.
+type JSONMetadata struct {
+	Title       string `json:"title"`
+	Channel     string `json:"channel"`
+	ChannelURL  string `json:"channel_url,omitempty"`
+	Duration    string `json:"duration"`
+	DurationSec int    `json:"duration_sec"`
+	UploadDate  string `json:"upload_date,omitempty"`
+	Description string `json:"description,omitempty"`
+	VideoID     string `json:"video_id,omitempty"`
+}
+
+// JSONStats holds transcription statistics, matching pipeline.Stats.
+type JSONStats struct {
+	Duration  string `json:"duration"`
+	WordCount int    `json:"word_count"`
+	Model     string `json:"model"`
+}
+
+// JSONSegment is a transcribed segment with times in seconds.
+type JSONSegment struct {
+	Start       float64     `json:"start"`
+	End         float64     `json:"end"`
+	Text        string      `json:"text"`
+	Speaker     string      `json:"speaker,omitempty"`
+	Probability float64     `json:"probability,omitempty"`
+	Tokens      []JSONToken `json:"tokens,omitempty"`
+}
+
+// JSONToken is a single whisper token with timing and confidence.
+type JSONToken struct {
+	Text        string  `json:"text"`
+	Start       float64 `json:"start"`
+	End         float64 `json:"end"`
+	Probability float64 `json:"probability"`
+}
+
+type jsonWriter struct{}
+
+func (jsonWriter) Name() string      { return FormatJSON }
+func (jsonWriter) Extension() string { return ".json" }
+
+func (jsonWriter) Write(meta *downloader.Metadata, segments []transcriber.Segment, cfg *config.TranscriptionConfig) (string, error) {
+	doc := JSONTranscript{
+		Source:      cfg.GetSource(),
+		Transcribed: time.Now().Format(time.RFC3339),
+		Language:    transcriptLanguage(segments, cfg),
+		Task:        cfg.Task,
+		Metadata: JSONMetadata{
+			Title:       meta.Title,
+			Channel:     meta.Channel,
+			ChannelURL:  meta.ChannelURL,
+			Duration:    meta.Duration,
+			DurationSec: meta.DurationSec,
+			UploadDate:  meta.UploadDate,
+			Description: meta.Description,
+			VideoID:     meta.VideoID,
+		},
+		Stats: JSONStats{
+			Duration:  meta.Duration,
+			WordCount: transcriber.CountWords(segments),
+			Model:     cfg.Model,
+		},
+		Segments: make([]JSONSegment, 0, len(segments)),
+	}
+
+	if cfg.Clipped() {
+		doc.Clip = &JSONClip{Start: cfg.Start.Seconds(), End: cfg.End.Seconds()}
+	}
+
+	for _, seg := range segments {
+		js := JSONSegment{
+			Start:       seg.StartTime().Seconds(),
+			End:         seg.EndTime().Seconds(),
+			Text:        seg.Text,
+			Speaker:     seg.Speaker,
+			Probability: seg.Probability,
+		}
+		for _, tok := range seg.Tokens {
+			js.Tokens = append(js.Tokens, JSONToken{
+				Text:        tok.Text,
+				Start:       tok.Start.Seconds(),
+				End:         tok.End.Seconds(),
+				Probability: tok.Probability,
+			})
+		}
+		doc.Segments = append(doc.Segments, js)
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encode json: %w", err)
+	}
+
+	return WriteFile(meta, cfg, ".json", string(data)+"\n")
+}
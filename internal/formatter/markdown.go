@@ -3,8 +3,6 @@ package formatter
 import (
 	"bytes"
 	"fmt"
-	"os"
-	"path/filepath"
 	"regexp"
 	"strings"
 	"text/template"
@@ -25,12 +23,30 @@ channel: "{{.Channel}}"
 uploaded: "{{.UploadDate}}"
 transcribed: "{{.TranscribedDate}}"
 duration: "{{.Duration}}"
-model: "whisper-{{.Model}}"
+model: "{{.Model}}"
+{{- if .Language}}
+language: "{{.Language}}"
+{{- end}}
+{{- if .Translated}}
+translated: "en"
+{{- end}}
+{{- if .ClipStart}}
+clip_start: "{{.ClipStart}}"
+{{- end}}
+{{- if .ClipEnd}}
+clip_end: "{{.ClipEnd}}"
+{{- end}}
 ---
 
 # {{.Title}}
 
 {{.Attribution}}
+{{- if .Contents}}
+
+## Contents
+
+{{.Contents}}
+{{- end}}
 
 ## Transcription
 
@@ -47,52 +63,38 @@ type MarkdownData struct {
 	TranscribedDate string
 	Duration        string
 	Model           string
+	Language        string
+	Translated      bool
+	ClipStart       string
+	ClipEnd         string
 	Attribution     string
+	Contents        string
 	Content         string
 }
 
-// GenerateMarkdown creates a Markdown file from transcription segments.
-func GenerateMarkdown(meta *downloader.Metadata, segments []transcriber.Segment, cfg *config.TranscriptionConfig) (string, error) {
-	var content strings.Builder
-
-	if cfg.Timestamps {
-		for _, seg := range segments {
-			// Format: **[00:00]** Text wrapped to 80 chars
-			timestamp := fmt.Sprintf("**[%s]**", seg.Timestamp)
-			text := strings.TrimSpace(seg.Text)
-			// Wrap text accounting for timestamp prefix on first line
-			wrapped := wrapTextWithPrefix(timestamp+" ", text, maxLineLength)
-			content.WriteString(wrapped)
-			content.WriteString("\n\n")
-		}
-	} else {
-		var paragraph strings.Builder
-		for i, seg := range segments {
-			paragraph.WriteString(seg.Text)
-			paragraph.WriteString(" ")
+// transcriptLanguage returns the language whisper detected, falling back
+// to the configured one unless that was auto.
+func transcriptLanguage(segments []transcriber.Segment, cfg *config.TranscriptionConfig) string {
+	if lang := transcriber.DetectedLanguage(segments); lang != "" {
+		return lang
+	}
+	if cfg.Language != transcriber.LanguageAuto {
+		return cfg.Language
+	}
+	return ""
+}
 
-			if strings.HasSuffix(seg.Text, ".") ||
-				strings.HasSuffix(seg.Text, "?") ||
-				strings.HasSuffix(seg.Text, "!") ||
-				(i+1)%5 == 0 {
-				text := strings.TrimSpace(paragraph.String())
-				if text != "" {
-					wrapped := wrapText(text, maxLineLength)
-					content.WriteString(wrapped)
-					content.WriteString("\n\n")
-				}
-				paragraph.Reset()
-			}
-		}
-		if paragraph.Len() > 0 {
-			text := strings.TrimSpace(paragraph.String())
-			if text != "" {
-				wrapped := wrapText(text, maxLineLength)
-				content.WriteString(wrapped)
-				content.WriteString("\n")
-			}
-		}
+// modelLabel names what produced the transcript.
+func modelLabel(model string) string {
+	if model == config.CaptionsModel {
+		return "youtube-captions"
 	}
+	return "whisper-" + model
+}
+
+// GenerateMarkdown creates a Markdown file from transcription segments.
+func GenerateMarkdown(meta *downloader.Metadata, segments []transcriber.Segment, cfg *config.TranscriptionConfig) (string, error) {
+	content, toc := renderChapters(clipChapters(meta.Chapters, cfg), segments, cfg, sanitizeTitle(meta.Title))
 
 	uploadDate := meta.UploadDate
 	if len(uploadDate) == 8 {
@@ -125,16 +127,18 @@ func GenerateMarkdown(meta *downloader.Metadata, segments []transcriber.Segment,
 		UploadDate:      uploadDate,
 		TranscribedDate: transcribedDate,
 		Duration:        meta.Duration,
-		Model:           cfg.Model,
+		Model:           modelLabel(cfg.Model),
+		Language:        transcriptLanguage(segments, cfg),
+		Translated:      cfg.Translate(),
 		Attribution:     attribution,
-		Content:         strings.TrimSpace(content.String()),
+		Contents:        toc,
+		Content:         content,
 	}
-
-	filename := slugify(meta.Title) + ".md"
-	outputPath := filepath.Join(cfg.OutputDir, filename)
-
-	if err := os.MkdirAll(cfg.OutputDir, 0755); err != nil {
-		return "", fmt.Errorf("create output dir: %w", err)
+	if cfg.Clipped() {
+		data.ClipStart = config.FormatClipTime(cfg.Start)
+		if cfg.End > 0 {
+			data.ClipEnd = config.FormatClipTime(cfg.End)
+		}
 	}
 
 	tmpl, err := template.New("markdown").Parse(markdownTemplate)
@@ -147,13 +151,87 @@ func GenerateMarkdown(meta *downloader.Metadata, segments []transcriber.Segment,
 		return "", fmt.Errorf("execute template: %w", err)
 	}
 
-	output := FixCommonIssues(buf.String())
+	return WriteFile(meta, cfg, ".md", FixCommonIssues(buf.String()))
+}
+
+// renderSegments renders segments as speaker turns, timestamped lines or
+// plain paragraphs.
+func renderSegments(segments []transcriber.Segment, cfg *config.TranscriptionConfig) string {
+	var content strings.Builder
 
-	if err := os.WriteFile(outputPath, []byte(output), 0644); err != nil {
-		return "", fmt.Errorf("write file: %w", err)
+	if transcriber.HasSpeakers(segments) {
+		writeSpeakerTurns(&content, segments, cfg.Timestamps)
+	} else if cfg.Timestamps {
+		for _, seg := range segments {
+			// Format: **[00:00]** Text wrapped to 80 chars
+			timestamp := fmt.Sprintf("**[%s]**", seg.Timestamp)
+			text := strings.TrimSpace(seg.Text)
+			// Wrap text accounting for timestamp prefix on first line
+			wrapped := wrapTextWithPrefix(timestamp+" ", text, maxLineLength)
+			content.WriteString(wrapped)
+			content.WriteString("\n\n")
+		}
+	} else {
+		var paragraph strings.Builder
+		for i, seg := range segments {
+			paragraph.WriteString(seg.Text)
+			paragraph.WriteString(" ")
+
+			if strings.HasSuffix(seg.Text, ".") ||
+				strings.HasSuffix(seg.Text, "?") ||
+				strings.HasSuffix(seg.Text, "!") ||
+				(i+1)%5 == 0 {
+				text := strings.TrimSpace(paragraph.String())
+				if text != "" {
+					wrapped := wrapText(text, maxLineLength)
+					content.WriteString(wrapped)
+					content.WriteString("\n\n")
+				}
+				paragraph.Reset()
+			}
+		}
+		if paragraph.Len() > 0 {
+			text := strings.TrimSpace(paragraph.String())
+			if text != "" {
+				wrapped := wrapText(text, maxLineLength)
+				content.WriteString(wrapped)
+				content.WriteString("\n")
+			}
+		}
 	}
+	return strings.TrimSpace(content.String())
+}
+
+// writeSpeakerTurns renders consecutive segments of the same speaker as
+// one paragraph labelled with the speaker, and the turn's start time if
+// timestamps are enabled.
+func writeSpeakerTurns(content *strings.Builder, segments []transcriber.Segment, timestamps bool) {
+	for i := 0; i < len(segments); {
+		speaker := segments[i].Speaker
+		if speaker == "" {
+			speaker = "Unknown"
+		}
+		label := speaker + ":"
+		if timestamps {
+			// The timestamp is already bracketed, e.g. [00:05].
+			label = segments[i].Timestamp + " " + label
+		}
 
-	return outputPath, nil
+		var turn []string
+		j := i
+		for ; j < len(segments) && segments[j].Speaker == segments[i].Speaker; j++ {
+			if text := strings.TrimSpace(segments[j].Text); text != "" {
+				turn = append(turn, text)
+			}
+		}
+		i = j
+
+		if len(turn) == 0 {
+			continue
+		}
+		content.WriteString(wrapTextWithPrefix("**"+label+"** ", strings.Join(turn, " "), maxLineLength))
+		content.WriteString("\n\n")
+	}
 }
 
 func sanitizeTitle(title string) string {
@@ -3,6 +3,7 @@ package formatter
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/cyber/whisper-transcribe/internal/config"
@@ -236,6 +237,61 @@ func TestGenerateMarkdownLongContent(t *testing.T) {
 	}
 }
 
+func TestGenerateMarkdownLanguage(t *testing.T) {
+	meta := &downloader.Metadata{Title: "Vortrag", Channel: "Kanal", Duration: "1:00"}
+	segments := []transcriber.Segment{{Text: "Welcome to the talk.", Language: "de"}}
+	cfg := &config.TranscriptionConfig{
+		URL:       "https://www.youtube.com/watch?v=lang123",
+		Model:     "small",
+		OutputDir: t.TempDir(),
+		Language:  "auto",
+		Task:      config.TaskTranslate,
+	}
+
+	outputPath, err := GenerateMarkdown(meta, segments, cfg)
+	if err != nil {
+		t.Fatalf("GenerateMarkdown failed: %v", err)
+	}
+	content, _ := os.ReadFile(outputPath)
+	frontmatter := strings.SplitN(string(content), "---", 3)[1]
+
+	for _, want := range []string{`language: "de"`, `translated: "en"`} {
+		if !strings.Contains(frontmatter, want) {
+			t.Errorf("frontmatter missing %s:\n%s", want, frontmatter)
+		}
+	}
+}
+
+func TestGenerateMarkdownSpeakers(t *testing.T) {
+	meta := &downloader.Metadata{Title: "Interview", Channel: "Podcast", Duration: "1:00"}
+	segments := []transcriber.Segment{
+		{Text: "Welcome to the show.", Timestamp: "[00:00]", Speaker: "Alice"},
+		{Text: "Today we talk about Go.", Timestamp: "[00:03]", Speaker: "Alice"},
+		{Text: "Thanks for having me.", Timestamp: "[00:07]", Speaker: "Bob"},
+	}
+	cfg := &config.TranscriptionConfig{
+		URL:        "https://www.youtube.com/watch?v=speak123",
+		Model:      "small",
+		Timestamps: true,
+		OutputDir:  t.TempDir(),
+	}
+
+	outputPath, err := GenerateMarkdown(meta, segments, cfg)
+	if err != nil {
+		t.Fatalf("GenerateMarkdown failed: %v", err)
+	}
+	content, _ := os.ReadFile(outputPath)
+
+	for _, want := range []string{
+		"**[00:00] Alice:** Welcome to the show. Today we talk about Go.\n\n",
+		"**[00:07] Bob:** Thanks for having me.\n",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("output missing %q:\n%s", want, content)
+		}
+	}
+}
+
 func splitLines(s string) []string {
 	var lines []string
 	start := 0
@@ -0,0 +1,218 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cyber/whisper-transcribe/internal/config"
+	"github.com/cyber/whisper-transcribe/internal/downloader"
+	"github.com/cyber/whisper-transcribe/internal/transcriber"
+)
+
+// Subtitle layout rules, following common broadcast caption guidelines.
+const (
+	maxCueLineLength = 42
+	maxCueLines      = 2
+	minCueDuration   = time.Second
+	maxCueDuration   = 7 * time.Second
+)
+
+// cue is a single subtitle entry.
+type cue struct {
+	start time.Duration
+	end   time.Duration
+	lines []string
+}
+
+// GenerateSRT creates a SubRip subtitle file from transcription segments.
+func GenerateSRT(meta *downloader.Metadata, segments []transcriber.Segment, cfg *config.TranscriptionConfig) (string, error) {
+	var b strings.Builder
+	for i, c := range buildCues(segments) {
+		fmt.Fprintf(&b, "%d\n", i+1)
+		fmt.Fprintf(&b, "%s --> %s\n", formatCueTime(c.start, ","), formatCueTime(c.end, ","))
+		b.WriteString(strings.Join(c.lines, "\n"))
+		b.WriteString("\n\n")
+	}
+
+	return WriteFile(meta, cfg, ".srt", b.String())
+}
+
+// GenerateVTT creates a WebVTT subtitle file from transcription segments.
+func GenerateVTT(meta *downloader.Metadata, segments []transcriber.Segment, cfg *config.TranscriptionConfig) (string, error) {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n\n")
+	for _, c := range buildCues(segments) {
+		fmt.Fprintf(&b, "%s --> %s\n", formatCueTime(c.start, "."), formatCueTime(c.end, "."))
+		for i, line := range c.lines {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			b.WriteString(escapeVTT(line))
+		}
+		b.WriteString("\n\n")
+	}
+
+	return WriteFile(meta, cfg, ".vtt", b.String())
+}
+
+// buildCues converts segments into cues that respect line length and
+// duration limits. Timing is taken from the segment boundaries; segments
+// that are too long are split, with time divided by text length.
+func buildCues(segments []transcriber.Segment) []cue {
+	var cues []cue
+
+	for _, seg := range segments {
+		text := strings.TrimSpace(seg.Text)
+		if text == "" {
+			continue
+		}
+
+		start := seg.StartTime()
+		end := seg.EndTime()
+		if end < start {
+			end = start
+		}
+
+		lines := wrapCueText(text)
+
+		// Use one line per cue when two-line cues would run too long.
+		perCue := maxCueLines
+		needed := int((end - start + maxCueDuration - 1) / maxCueDuration)
+		if needed > (len(lines)+perCue-1)/perCue {
+			perCue = 1
+		}
+
+		var groups [][]string
+		for i := 0; i < len(lines); i += perCue {
+			groups = append(groups, lines[i:min(i+perCue, len(lines))])
+		}
+		if len(groups) < needed {
+			// Even single lines would stay up too long, so split them.
+			groups = splitWords(strings.Fields(strings.Join(lines, " ")), needed)
+		}
+
+		for i, span := range cueSpans(groups, start, end) {
+			cues = append(cues, cue{start: span[0], end: span[1], lines: groups[i]})
+		}
+	}
+
+	// Enforce minimum durations without overlapping the following cue.
+	for i := range cues {
+		if cues[i].end-cues[i].start < minCueDuration {
+			cues[i].end = cues[i].start + minCueDuration
+		}
+		if i+1 < len(cues) && cues[i].end > cues[i+1].start {
+			cues[i].end = max(cues[i+1].start, cues[i].start)
+		}
+	}
+
+	return cues
+}
+
+// cueSpans divides start to end between the groups by text length. If that
+// keeps a group up longer than maxCueDuration, the time is divided evenly
+// instead, and a group that still runs too long is cut short.
+func cueSpans(groups [][]string, start, end time.Duration) [][2]time.Duration {
+	totalChars := 0
+	for _, group := range groups {
+		totalChars += groupLength(group)
+	}
+
+	spans := make([][2]time.Duration, len(groups))
+	even := false
+	cursor := start
+	for i, group := range groups {
+		groupEnd := end
+		if i < len(groups)-1 {
+			share := float64(groupLength(group)) / float64(totalChars)
+			groupEnd = cursor + time.Duration(share*float64(end-start))
+		}
+		spans[i] = [2]time.Duration{cursor, groupEnd}
+		even = even || groupEnd-cursor > maxCueDuration
+		cursor = groupEnd
+	}
+
+	if even {
+		step := (end - start) / time.Duration(len(groups))
+		for i := range spans {
+			spans[i][0] = start + time.Duration(i)*step
+			spans[i][1] = min(spans[i][0]+step, spans[i][0]+maxCueDuration)
+			if i == len(spans)-1 && step <= maxCueDuration {
+				spans[i][1] = end
+			}
+		}
+	}
+	return spans
+}
+
+// splitWords divides words into n groups of similar length, or one group
+// per word if there are fewer, each wrapped to the cue line length.
+func splitWords(words []string, n int) [][]string {
+	n = min(n, len(words))
+	total := 0
+	for _, w := range words {
+		total += len(w) + 1
+	}
+
+	var groups [][]string
+	var current []string
+	length := 0
+	for i, w := range words {
+		current = append(current, w)
+		length += len(w) + 1
+		left := len(words) - i - 1
+		remaining := n - len(groups) - 1
+		if remaining > 0 && (length*n >= total*(len(groups)+1) || left == remaining) {
+			groups = append(groups, wrapCueText(strings.Join(current, " ")))
+			current = nil
+		}
+	}
+	return append(groups, wrapCueText(strings.Join(current, " ")))
+}
+
+// wrapCueText wraps text to the cue line length, breaking words that are
+// longer than a line.
+func wrapCueText(text string) []string {
+	var words []string
+	for _, w := range strings.Fields(text) {
+		for len(w) > maxCueLineLength {
+			cut := maxCueLineLength
+			for cut > 0 && !utf8.RuneStart(w[cut]) {
+				cut--
+			}
+			words = append(words, w[:cut])
+			w = w[cut:]
+		}
+		words = append(words, w)
+	}
+	return strings.Split(wrapText(strings.Join(words, " "), maxCueLineLength), "\n")
+}
+
+func groupLength(lines []string) int {
+	n := 0
+	for _, line := range lines {
+		n += len(line)
+	}
+	return max(n, 1)
+}
+
+// formatCueTime formats a duration as HH:MM:SS<sep>mmm.
+func formatCueTime(d time.Duration, sep string) string {
+	if d < 0 {
+		d = 0
+	}
+	ms := d.Milliseconds()
+	h := ms / 3_600_000
+	m := (ms % 3_600_000) / 60_000
+	s := (ms % 60_000) / 1000
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", h, m, s, sep, ms%1000)
+}
+
+func escapeVTT(s string) string {
+	s = strings.ReplaceAll(s, "&", "&amp;")
+	s = strings.ReplaceAll(s, "<", "&lt;")
+	s = strings.ReplaceAll(s, ">", "&gt;")
+	return s
+}
@@ -0,0 +1,121 @@
+package formatter
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cyber/whisper-transcribe/internal/config"
+	"github.com/cyber/whisper-transcribe/internal/downloader"
+	"github.com/cyber/whisper-transcribe/internal/transcriber"
+)
+
+func TestBuildCuesLimits(t *testing.T) {
+	segments := []transcriber.Segment{
+		{Start: "00:00:00.000", End: "00:00:00.400", Text: "Hi."},
+		{Start: "00:00:02.000", End: "00:00:20.000", Text: "This segment is long enough that it has to be split across several subtitle cues to respect the line length and duration rules."},
+		{Start: "00:00:20.000", End: "00:00:21.000", Text: "  "},
+	}
+
+	cues := buildCues(segments)
+	if len(cues) < 3 {
+		t.Fatalf("expected long segment to be split, got %d cues", len(cues))
+	}
+
+	if cues[0].end-cues[0].start != minCueDuration {
+		t.Errorf("short cue not extended to minimum: %v", cues[0].end-cues[0].start)
+	}
+
+	if cues[1].start != 2*time.Second {
+		t.Errorf("split cue should start at segment boundary, got %v", cues[1].start)
+	}
+	if last := cues[len(cues)-1]; last.start >= 20*time.Second || last.end < 20*time.Second {
+		t.Errorf("last cue should reach segment end, got %v-%v", last.start, last.end)
+	}
+
+	for i, c := range cues {
+		if len(c.lines) > maxCueLines {
+			t.Errorf("cue %d has %d lines", i, len(c.lines))
+		}
+		for _, line := range c.lines {
+			if len(line) > maxCueLineLength {
+				t.Errorf("cue %d line too long (%d): %q", i, len(line), line)
+			}
+		}
+		if i > 0 && c.start < cues[i-1].end {
+			t.Errorf("cue %d overlaps previous cue", i)
+		}
+	}
+}
+
+func TestBuildCuesSplitsByTime(t *testing.T) {
+	segments := []transcriber.Segment{
+		{Start: "00:00:00.000", End: "00:00:20.000", Text: "One line that is said very slowly."},
+		{Start: "00:00:20.000", End: "00:00:22.000", Text: "https://example.com/a/very/long/path/that/does/not/fit/on/one/line"},
+	}
+
+	cues := buildCues(segments)
+	var slow []cue
+	for _, c := range cues {
+		if c.start < 20*time.Second {
+			slow = append(slow, c)
+		}
+		if c.end-c.start > maxCueDuration {
+			t.Errorf("cue %v-%v runs longer than %v", c.start, c.end, maxCueDuration)
+		}
+		for _, line := range c.lines {
+			if len(line) > maxCueLineLength {
+				t.Errorf("line too long (%d): %q", len(line), line)
+			}
+		}
+	}
+	if len(slow) < 3 {
+		t.Fatalf("expected a 20s single-line segment to be split in time, got %d cues", len(slow))
+	}
+	if last := slow[len(slow)-1]; last.end != 20*time.Second {
+		t.Errorf("last cue should reach segment end, got %v", last.end)
+	}
+
+	var words []string
+	for _, c := range slow {
+		words = append(words, c.lines...)
+	}
+	if got := strings.Join(words, " "); got != segments[0].Text {
+		t.Errorf("split cues lost text: %q", got)
+	}
+}
+
+func TestGenerateSubtitles(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	meta := &downloader.Metadata{Title: "Subtitle Test"}
+	segments := []transcriber.Segment{
+		{Start: "00:00:01.500", End: "00:00:04.250", Text: "Fish & chips <tonight>."},
+		{Start: "01:02:03.004", End: "01:02:05.000", Text: "Second cue."},
+	}
+	cfg := &config.TranscriptionConfig{OutputDir: tmpDir}
+
+	srtPath, err := GenerateSRT(meta, segments, cfg)
+	if err != nil {
+		t.Fatalf("GenerateSRT failed: %v", err)
+	}
+	srt, _ := os.ReadFile(srtPath)
+	wantSRT := "1\n00:00:01,500 --> 00:00:04,250\nFish & chips <tonight>.\n\n" +
+		"2\n01:02:03,004 --> 01:02:05,000\nSecond cue.\n\n"
+	if string(srt) != wantSRT {
+		t.Errorf("unexpected SRT output:\n%s", srt)
+	}
+
+	vttPath, err := GenerateVTT(meta, segments, cfg)
+	if err != nil {
+		t.Fatalf("GenerateVTT failed: %v", err)
+	}
+	vtt, _ := os.ReadFile(vttPath)
+	if !strings.HasPrefix(string(vtt), "WEBVTT\n\n00:00:01.500 --> 00:00:04.250\n") {
+		t.Errorf("unexpected VTT header:\n%s", vtt)
+	}
+	if !strings.Contains(string(vtt), "Fish &amp; chips &lt;tonight&gt;.") {
+		t.Errorf("VTT text not escaped:\n%s", vtt)
+	}
+}
@@ -0,0 +1,24 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cyber/whisper-transcribe/internal/config"
+	"github.com/cyber/whisper-transcribe/internal/downloader"
+	"github.com/cyber/whisper-transcribe/internal/transcriber"
+)
+
+// WriteWERReport writes the word error rate of the whisper transcript
+// against the video's captions next to the transcript.
+func WriteWERReport(meta *downloader.Metadata, cfg *config.TranscriptionConfig, captionLang string, w transcriber.WER) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Word error rate of whisper-%s against %s captions\n", cfg.Model, captionLang)
+	fmt.Fprintf(&b, "Source: %s\n\n", cfg.GetSource())
+	fmt.Fprintf(&b, "WER:             %.2f%%\n", w.Rate()*100)
+	fmt.Fprintf(&b, "Reference words: %d\n", w.ReferenceWords)
+	fmt.Fprintf(&b, "Substitutions:   %d\n", w.Substitutions)
+	fmt.Fprintf(&b, "Deletions:       %d\n", w.Deletions)
+	fmt.Fprintf(&b, "Insertions:      %d\n", w.Insertions)
+	return WriteFile(meta, cfg, ".wer.txt", b.String())
+}
@@ -0,0 +1,164 @@
+package formatter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cyber/whisper-transcribe/internal/config"
+	"github.com/cyber/whisper-transcribe/internal/downloader"
+	"github.com/cyber/whisper-transcribe/internal/transcriber"
+)
+
+// Built-in output format names.
+const (
+	FormatMarkdown = "markdown"
+	FormatSRT      = "srt"
+	FormatVTT      = "vtt"
+)
+
+// OutputWriter renders transcription segments in a single output format.
+type OutputWriter interface {
+	// Name is the format name used in --format and output_formats.
+	Name() string
+	// Extension is the file extension of the output, including the dot.
+	Extension() string
+	// Write renders the transcript and returns the path of the written file.
+	Write(meta *downloader.Metadata, segments []transcriber.Segment, cfg *config.TranscriptionConfig) (string, error)
+}
+
+var (
+	registryMu sync.RWMutex
+	writers    = map[string]OutputWriter{}
+	aliases    = map[string]string{}
+)
+
+func init() {
+	Register(markdownWriter{}, "md")
+	Register(srtWriter{})
+	Register(vttWriter{}, "webvtt")
+}
+
+// Register adds an output writer to the registry. Aliases are alternative
+// names that resolve to the same writer. Registering a name twice replaces
+// the previous writer.
+func Register(w OutputWriter, alias ...string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	name := strings.ToLower(w.Name())
+	writers[name] = w
+	for _, a := range alias {
+		aliases[strings.ToLower(a)] = name
+	}
+}
+
+// Lookup returns the writer registered under name or one of its aliases.
+func Lookup(name string) (OutputWriter, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	key := strings.ToLower(strings.TrimSpace(name))
+	if target, ok := aliases[key]; ok {
+		key = target
+	}
+	if w, ok := writers[key]; ok {
+		return w, nil
+	}
+	return nil, fmt.Errorf("unknown output format: %s (supported: %s)",
+		name, strings.Join(namesLocked(), ", "))
+}
+
+// Names returns the names of all registered writers, sorted.
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return namesLocked()
+}
+
+func namesLocked() []string {
+	names := make([]string, 0, len(writers))
+	for name := range writers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WritersFor resolves a list of format names to their writers.
+func WritersFor(names []string) ([]OutputWriter, error) {
+	var result []OutputWriter
+	for _, name := range names {
+		w, err := Lookup(name)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, w)
+	}
+	return result, nil
+}
+
+type markdownWriter struct{}
+
+func (markdownWriter) Name() string      { return FormatMarkdown }
+func (markdownWriter) Extension() string { return ".md" }
+
+func (markdownWriter) Write(meta *downloader.Metadata, segments []transcriber.Segment, cfg *config.TranscriptionConfig) (string, error) {
+	return GenerateMarkdown(meta, segments, cfg)
+}
+
+type srtWriter struct{}
+
+func (srtWriter) Name() string      { return FormatSRT }
+func (srtWriter) Extension() string { return ".srt" }
+
+func (srtWriter) Write(meta *downloader.Metadata, segments []transcriber.Segment, cfg *config.TranscriptionConfig) (string, error) {
+	return GenerateSRT(meta, segments, cfg)
+}
+
+type vttWriter struct{}
+
+func (vttWriter) Name() string      { return FormatVTT }
+func (vttWriter) Extension() string { return ".vtt" }
+
+func (vttWriter) Write(meta *downloader.Metadata, segments []transcriber.Segment, cfg *config.TranscriptionConfig) (string, error) {
+	return GenerateVTT(meta, segments, cfg)
+}
+
+// OutputPath returns the path a writer should use for a transcript, based
+// on the slugified video title and the given extension.
+func OutputPath(meta *downloader.Metadata, cfg *config.TranscriptionConfig, ext string) string {
+	return filepath.Join(cfg.OutputDir, slugify(meta.Title)+ext)
+}
+
+// PrimaryOutput picks the Markdown transcript if there is one, otherwise the
+// first output file.
+func PrimaryOutput(paths []string) string {
+	for _, p := range paths {
+		if filepath.Ext(p) == ".md" {
+			return p
+		}
+	}
+	if len(paths) > 0 {
+		return paths[0]
+	}
+	return ""
+}
+
+// WriteFile writes rendered content to the writer's output path, creating
+// the output directory if needed.
+func WriteFile(meta *downloader.Metadata, cfg *config.TranscriptionConfig, ext, content string) (string, error) {
+	if err := os.MkdirAll(cfg.OutputDir, 0755); err != nil {
+		return "", fmt.Errorf("create output dir: %w", err)
+	}
+
+	outputPath := OutputPath(meta, cfg, ext)
+	if err := os.WriteFile(outputPath, []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("write file: %w", err)
+	}
+
+	return outputPath, nil
+}
@@ -0,0 +1,105 @@
+package formatter
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cyber/whisper-transcribe/internal/config"
+	"github.com/cyber/whisper-transcribe/internal/downloader"
+	"github.com/cyber/whisper-transcribe/internal/transcriber"
+)
+
+type textWriter struct{}
+
+func (textWriter) Name() string      { return "text" }
+func (textWriter) Extension() string { return ".txt" }
+
+func (textWriter) Write(meta *downloader.Metadata, segments []transcriber.Segment, cfg *config.TranscriptionConfig) (string, error) {
+	return WriteFile(meta, cfg, ".txt", "")
+}
+
+// unregister removes a writer registered by a test and its aliases, so that
+// it doesn't leak into other tests.
+func unregister(name string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	delete(writers, name)
+	for alias, target := range aliases {
+		if target == name {
+			delete(aliases, alias)
+		}
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	for _, name := range []string{"markdown", "MD", "srt", "webvtt"} {
+		if _, err := Lookup(name); err != nil {
+			t.Errorf("Lookup(%q) failed: %v", name, err)
+		}
+	}
+
+	if _, err := Lookup("text"); err == nil {
+		t.Fatal("expected unknown format error")
+	}
+
+	Register(textWriter{}, "txt")
+	t.Cleanup(func() { unregister("text") })
+	writers, err := WritersFor([]string{"txt", "markdown"})
+	if err != nil {
+		t.Fatalf("WritersFor failed: %v", err)
+	}
+	if writers[0].Name() != "text" || writers[1].Extension() != ".md" {
+		t.Errorf("unexpected writers: %v", writers)
+	}
+}
+
+func TestJSONWriter(t *testing.T) {
+	cfg := &config.TranscriptionConfig{
+		URL:       "https://www.youtube.com/watch?v=json123",
+		Model:     "base",
+		OutputDir: t.TempDir(),
+	}
+	meta := &downloader.Metadata{Title: "JSON Test", Duration: "0:05", VideoID: "json123"}
+	segments := []transcriber.Segment{{
+		Start: "00:00:01.250",
+		End:   "00:00:02.500",
+		Text:  "Hello there",
+		Tokens: []transcriber.Token{
+			{Text: " Hello", Start: 1250 * time.Millisecond, End: 1800 * time.Millisecond, Probability: 0.9},
+		},
+	}}
+
+	w, _ := Lookup("json")
+	path, err := w.Write(meta, segments, cfg)
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	var doc JSONTranscript
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if doc.Metadata.VideoID != "json123" || doc.Stats.WordCount != 2 || doc.Stats.Model != "base" {
+		t.Errorf("unexpected header: %+v %+v", doc.Metadata, doc.Stats)
+	}
+	seg := doc.Segments[0]
+	if seg.Start != 1.25 || seg.End != 2.5 {
+		t.Errorf("unexpected segment times: %v-%v", seg.Start, seg.End)
+	}
+	if len(seg.Tokens) != 1 || seg.Tokens[0].End != 1.8 || seg.Tokens[0].Probability != 0.9 {
+		t.Errorf("unexpected tokens: %+v", seg.Tokens)
+	}
+}
+
+func TestPrimaryOutput(t *testing.T) {
+	if got := PrimaryOutput([]string{"talk.srt", "talk.md"}); got != "talk.md" {
+		t.Errorf("PrimaryOutput = %q, want the Markdown file", got)
+	}
+	if got := PrimaryOutput([]string{"talk.srt", "talk.vtt"}); got != "talk.srt" {
+		t.Errorf("PrimaryOutput = %q, want the first file", got)
+	}
+}
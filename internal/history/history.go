@@ -0,0 +1,193 @@
+// Package history keeps a local record of completed transcriptions so they
+// can be listed, searched and re-opened later.
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cyber/whisper-transcribe/internal/downloader"
+)
+
+// Entry records one completed transcription.
+type Entry struct {
+	ID          int                 `json:"id"`
+	Source      string              `json:"source"`
+	Metadata    downloader.Metadata `json:"metadata"`
+	Model       string              `json:"model"`
+	OutputPaths []string            `json:"output_paths"`
+	WordCount   int                 `json:"word_count"`
+	StartedAt   time.Time           `json:"started_at"`
+	CompletedAt time.Time           `json:"completed_at"`
+}
+
+// Store is a history file. The file is a JSON array that is rewritten in
+// full on every change, which keeps it easy to sync between machines.
+type Store struct {
+	path string
+}
+
+// mu serializes writes from concurrent pipelines in this process.
+var mu sync.Mutex
+
+// DefaultPath returns the history file location, following the XDG data
+// directory convention.
+func DefaultPath() string {
+	dir := os.Getenv("XDG_DATA_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "history.json"
+		}
+		dir = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dir, "whisper-transcribe", "history.json")
+}
+
+// Open returns the store at path, or at DefaultPath if path is empty. The
+// file is created on the first Add.
+func Open(path string) *Store {
+	if path == "" {
+		path = DefaultPath()
+	}
+	return &Store{path: path}
+}
+
+// Path returns the history file path.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Add appends an entry, assigning it the next ID.
+func (s *Store) Add(e Entry) (Entry, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return e, err
+	}
+
+	e.ID = 1
+	for _, existing := range entries {
+		e.ID = max(e.ID, existing.ID+1)
+	}
+	entries = append(entries, e)
+
+	if err := s.save(entries); err != nil {
+		return e, err
+	}
+	return e, nil
+}
+
+// List returns all entries, most recent first.
+func (s *Store) List() ([]Entry, error) {
+	mu.Lock()
+	entries, err := s.load()
+	mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].CompletedAt.After(entries[j].CompletedAt)
+	})
+	return entries, nil
+}
+
+// Search returns the entries whose title, channel, source or model contain
+// query, ignoring case. Results are most recent first.
+func (s *Store) Search(query string) ([]Entry, error) {
+	entries, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []Entry
+	for _, e := range entries {
+		if e.Matches(query) {
+			matches = append(matches, e)
+		}
+	}
+	return matches, nil
+}
+
+// Get returns the entry with the given ID.
+func (s *Store) Get(id int) (*Entry, error) {
+	entries, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.ID == id {
+			return &e, nil
+		}
+	}
+	return nil, fmt.Errorf("no history entry %d", id)
+}
+
+// Title returns the video title, falling back to the source.
+func (e *Entry) Title() string {
+	if e.Metadata.Title != "" {
+		return e.Metadata.Title
+	}
+	return e.Source
+}
+
+// Matches reports whether the entry's title, channel, source or model
+// contain query, ignoring case.
+func (e *Entry) Matches(query string) bool {
+	query = strings.ToLower(strings.TrimSpace(query))
+	for _, field := range []string{e.Metadata.Title, e.Metadata.Channel, e.Source, e.Model} {
+		if strings.Contains(strings.ToLower(field), query) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store) load() ([]Entry, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read history: %w", err)
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("parse history: %w", err)
+	}
+	return entries, nil
+}
+
+// save writes entries to a temporary file and renames it into place so a
+// crash never leaves a truncated history behind.
+func (s *Store) save(entries []Entry) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("create history dir: %w", err)
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode history: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("write history: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("write history: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cyber/whisper-transcribe/internal/downloader"
+)
+
+func TestStore(t *testing.T) {
+	store := Open(filepath.Join(t.TempDir(), "sub", "history.json"))
+
+	entries, err := store.List()
+	if err != nil {
+		t.Fatalf("List on missing file: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected empty history, got %d entries", len(entries))
+	}
+
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	first, err := store.Add(Entry{
+		Source:      "https://www.youtube.com/watch?v=abc",
+		Metadata:    downloader.Metadata{Title: "Go Concurrency Patterns", Channel: "GopherCon", VideoID: "abc"},
+		Model:       "base",
+		OutputPaths: []string{"/tmp/go.md"},
+		WordCount:   1200,
+		CompletedAt: base,
+	})
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	second, err := store.Add(Entry{
+		Source:      "/audio/standup.wav",
+		Metadata:    downloader.Metadata{Title: "standup"},
+		Model:       "small",
+		CompletedAt: base.Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if first.ID != 1 || second.ID != 2 {
+		t.Errorf("IDs = %d, %d, want 1, 2", first.ID, second.ID)
+	}
+
+	entries, err = store.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(entries) != 2 || entries[0].ID != 2 {
+		t.Errorf("List should return newest first, got %+v", entries)
+	}
+
+	matches, err := store.Search("gophercon")
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(matches) != 1 || matches[0].ID != 1 {
+		t.Errorf("Search(gophercon) = %+v", matches)
+	}
+
+	got, err := store.Get(1)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Metadata.VideoID != "abc" || got.WordCount != 1200 || got.OutputPaths[0] != "/tmp/go.md" {
+		t.Errorf("Get(1) = %+v", got)
+	}
+	if _, err := store.Get(3); err == nil {
+		t.Error("Get of missing ID should fail")
+	}
+}
@@ -0,0 +1,150 @@
+package models
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Auto is the model name that selects a model from the machine's resources.
+const Auto = "auto"
+
+// DefaultTargetRTF is the default real-time factor for Auto: transcribing
+// should take no longer than the audio itself.
+const DefaultTargetRTF = 1.0
+
+// Resources describes the machine a model has to run on.
+type Resources struct {
+	// AvailableMB is the memory available for a new process, or zero if
+	// unknown.
+	AvailableMB int64
+	Cores       int
+}
+
+// autoCandidate is a model Auto may pick, with rough resource estimates.
+type autoCandidate struct {
+	name string
+	// memoryMB is the approximate peak memory of whisper.cpp with this model.
+	memoryMB int64
+	// cost is the compute cost relative to tiny.
+	cost float64
+}
+
+// autoCandidates are in order of preference: the first one that fits is
+// chosen. Multilingual models only, since the language is not known.
+var autoCandidates = []autoCandidate{
+	{"large-v3", 3900, 32},
+	{"large-v3-turbo", 2200, 10},
+	{"large-v3-turbo-q8_0", 1550, 9},
+	{"large-v3-turbo-q5_0", 1250, 8},
+	{"medium", 2100, 16},
+	{"medium-q8_0", 1400, 14.5},
+	{"medium-q5_0", 1100, 13},
+	{"small", 850, 6},
+	{"small-q8_0", 640, 5.5},
+	{"small-q5_1", 570, 5},
+	{"base", 390, 2},
+	{"base-q8_0", 330, 1.8},
+	{"base-q5_1", 300, 1.6},
+	{"tiny", 270, 1},
+	{"tiny-q5_1", 230, 0.8},
+}
+
+// tinyRTFPerCore is the estimated real-time factor of tiny on one core.
+const tinyRTFPerCore = 0.15
+
+// memoryHeadroom is the fraction of available memory a model may use.
+const memoryHeadroom = 0.8
+
+// unknownMemoryMB is assumed where available memory can't be detected.
+const unknownMemoryMB = 4096
+
+// DetectResources reads available memory from /proc/meminfo and the
+// number of CPU cores. Memory is left at zero where /proc is unavailable.
+func DetectResources() Resources {
+	res := Resources{Cores: runtime.NumCPU()}
+	if f, err := os.Open("/proc/meminfo"); err == nil {
+		defer f.Close()
+		if mb, err := parseMemAvailable(f); err == nil {
+			res.AvailableMB = mb
+		}
+	}
+	return res
+}
+
+// parseMemAvailable returns MemAvailable from /proc/meminfo in megabytes.
+// Kernels before 3.14 lack it, so MemFree plus page cache is used instead.
+func parseMemAvailable(r io.Reader) (int64, error) {
+	fields := make(map[string]int64)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		kb, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimSpace(value), " kB"), 10, 64)
+		if err != nil {
+			continue
+		}
+		fields[key] = kb
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("read meminfo: %w", err)
+	}
+
+	if kb, ok := fields["MemAvailable"]; ok {
+		return kb / 1024, nil
+	}
+	if free, ok := fields["MemFree"]; ok {
+		return (free + fields["Buffers"] + fields["Cached"]) / 1024, nil
+	}
+	return 0, fmt.Errorf("meminfo has no MemAvailable or MemFree")
+}
+
+// estimateRTF estimates the real-time factor of a candidate on cores CPU
+// cores. Scaling across cores is sublinear.
+func estimateRTF(c autoCandidate, cores int) float64 {
+	return c.cost * tinyRTFPerCore / math.Pow(float64(max(cores, 1)), 0.8)
+}
+
+// SelectAuto picks the most accurate model that fits in memory and is
+// expected to run at or below targetRTF. It falls back to the smallest
+// model if none fits. The reason describes the choice for the user.
+func SelectAuto(res Resources, targetRTF float64) (name, reason string) {
+	if targetRTF <= 0 {
+		targetRTF = DefaultTargetRTF
+	}
+	available := res.AvailableMB
+	if available <= 0 {
+		available = unknownMemoryMB
+	}
+	budget := int64(float64(available) * memoryHeadroom)
+
+	for _, c := range autoCandidates {
+		if c.memoryMB > budget {
+			continue
+		}
+		rtf := estimateRTF(c, res.Cores)
+		if rtf > targetRTF {
+			continue
+		}
+		return c.name, fmt.Sprintf("%s, %d cores, estimated real-time factor %.2f",
+			memoryString(res.AvailableMB), res.Cores, rtf)
+	}
+
+	last := autoCandidates[len(autoCandidates)-1]
+	return last.name, fmt.Sprintf("no model fits %s and %d cores at real-time factor %.2f; using the smallest",
+		memoryString(res.AvailableMB), res.Cores, targetRTF)
+}
+
+func memoryString(mb int64) string {
+	if mb <= 0 {
+		return "RAM unknown"
+	}
+	return FormatBytes(mb*1024*1024) + " RAM free"
+}
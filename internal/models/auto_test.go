@@ -0,0 +1,52 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseMemAvailable(t *testing.T) {
+	modern := "MemTotal:       16318496 kB\nMemFree:         1203528 kB\nMemAvailable:    8388608 kB\nBuffers:          204800 kB\n"
+	if mb, err := parseMemAvailable(strings.NewReader(modern)); err != nil || mb != 8192 {
+		t.Errorf("MemAvailable = %d, %v; want 8192", mb, err)
+	}
+
+	old := "MemTotal: 4096000 kB\nMemFree: 1048576 kB\nBuffers: 524288 kB\nCached: 524288 kB\n"
+	if mb, err := parseMemAvailable(strings.NewReader(old)); err != nil || mb != 2048 {
+		t.Errorf("fallback = %d, %v; want 2048", mb, err)
+	}
+
+	if _, err := parseMemAvailable(strings.NewReader("garbage\n")); err == nil {
+		t.Error("expected error without memory fields")
+	}
+}
+
+func TestSelectAuto(t *testing.T) {
+	cases := []struct {
+		res  Resources
+		rtf  float64
+		want string
+	}{
+		{Resources{AvailableMB: 16384, Cores: 8}, 1, "large-v3"},
+		{Resources{AvailableMB: 16384, Cores: 4}, 1, "large-v3-turbo"},
+		{Resources{AvailableMB: 2048, Cores: 4}, 1, "large-v3-turbo-q8_0"},
+		{Resources{AvailableMB: 16384, Cores: 4}, 0.3, "small"},
+		{Resources{AvailableMB: 512, Cores: 1}, 1, "base"},
+		{Resources{AvailableMB: 100, Cores: 1}, 1, "tiny-q5_1"},
+		{Resources{Cores: 8}, 1, "large-v3-turbo"}, // unknown memory assumes 4 GB
+	}
+	for _, c := range cases {
+		got, reason := SelectAuto(c.res, c.rtf)
+		if got != c.want {
+			t.Errorf("SelectAuto(%+v, %.1f) = %s (%s), want %s", c.res, c.rtf, got, reason, c.want)
+		}
+	}
+}
+
+func TestAutoCandidatesAreRegistered(t *testing.T) {
+	for _, c := range autoCandidates {
+		if _, err := GetModelInfo(c.name); err != nil {
+			t.Errorf("auto candidate %s: %v", c.name, err)
+		}
+	}
+}
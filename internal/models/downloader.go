@@ -1,6 +1,7 @@
 package models
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -19,26 +20,52 @@ type ModelInfo struct {
 	Filename string
 	Size     string
 	URL      string
+	// SHA1 and SHA256 are hex checksums of the model file. Downloads are
+	// verified against whichever is set.
+	SHA1   string
+	SHA256 string
 }
 
 // ProgressFunc is called with download progress (bytesDownloaded, totalBytes).
 type ProgressFunc func(downloaded, total int64)
 
-// AvailableModels returns information about all available models.
-func AvailableModels() []ModelInfo {
+// builtinModels lists the whisper.cpp models published on Hugging Face.
+// URLs are filled in by AvailableModels from the configured base URL.
+func builtinModels() []ModelInfo {
 	return []ModelInfo{
-		{Name: "tiny", Filename: "ggml-tiny.bin", Size: "75 MB", URL: HuggingFaceBaseURL + "/ggml-tiny.bin"},
-		{Name: "tiny.en", Filename: "ggml-tiny.en.bin", Size: "75 MB", URL: HuggingFaceBaseURL + "/ggml-tiny.en.bin"},
-		{Name: "base", Filename: "ggml-base.bin", Size: "142 MB", URL: HuggingFaceBaseURL + "/ggml-base.bin"},
-		{Name: "base.en", Filename: "ggml-base.en.bin", Size: "142 MB", URL: HuggingFaceBaseURL + "/ggml-base.en.bin"},
-		{Name: "small", Filename: "ggml-small.bin", Size: "466 MB", URL: HuggingFaceBaseURL + "/ggml-small.bin"},
-		{Name: "small.en", Filename: "ggml-small.en.bin", Size: "466 MB", URL: HuggingFaceBaseURL + "/ggml-small.en.bin"},
-		{Name: "medium", Filename: "ggml-medium.bin", Size: "1.5 GB", URL: HuggingFaceBaseURL + "/ggml-medium.bin"},
-		{Name: "medium.en", Filename: "ggml-medium.en.bin", Size: "1.5 GB", URL: HuggingFaceBaseURL + "/ggml-medium.en.bin"},
-		{Name: "large-v1", Filename: "ggml-large-v1.bin", Size: "2.9 GB", URL: HuggingFaceBaseURL + "/ggml-large-v1.bin"},
-		{Name: "large-v2", Filename: "ggml-large-v2.bin", Size: "2.9 GB", URL: HuggingFaceBaseURL + "/ggml-large-v2.bin"},
-		{Name: "large-v3", Filename: "ggml-large-v3.bin", Size: "2.9 GB", URL: HuggingFaceBaseURL + "/ggml-large-v3.bin"},
-		{Name: "large", Filename: "ggml-large-v3.bin", Size: "2.9 GB", URL: HuggingFaceBaseURL + "/ggml-large-v3.bin"},
+		{Name: "tiny", Filename: "ggml-tiny.bin", Size: "75 MB", SHA1: "bd577a113a864445d4c299885e0cb97d4ba92b5f"},
+		{Name: "tiny.en", Filename: "ggml-tiny.en.bin", Size: "75 MB", SHA1: "c78c86eb1a8faa21b369bcd33207cc90d64ae9df"},
+		{Name: "base", Filename: "ggml-base.bin", Size: "142 MB", SHA1: "465707469ff3a37a2b9b8d8f89f2f99de7299dac"},
+		{Name: "base.en", Filename: "ggml-base.en.bin", Size: "142 MB", SHA1: "137c40403d78fd54d454da0f9bd998f78703390c"},
+		{Name: "small", Filename: "ggml-small.bin", Size: "466 MB", SHA1: "55356645c2b361a969dfd0ef2c5a50d530afd8d5"},
+		{Name: "small.en", Filename: "ggml-small.en.bin", Size: "466 MB", SHA1: "db8a495a91d927739e50b3fc1cc4c6b8f6c2d022"},
+		{Name: "medium", Filename: "ggml-medium.bin", Size: "1.5 GB", SHA1: "fd9727b6e1217c2f614f9b698455c4ffd82463b4"},
+		{Name: "medium.en", Filename: "ggml-medium.en.bin", Size: "1.5 GB", SHA1: "8c30f0e44ce9560643ebd10bbe50cd20eafd3723"},
+		{Name: "large-v1", Filename: "ggml-large-v1.bin", Size: "2.9 GB", SHA1: "b1caaf735c4cc1429223d5a74f0f4d0b9b59a299"},
+		{Name: "large-v2", Filename: "ggml-large-v2.bin", Size: "2.9 GB", SHA1: "0f4c8e34f21cf1a914c59d8b3ce882345ad349d6"},
+		{Name: "large-v3", Filename: "ggml-large-v3.bin", Size: "2.9 GB", SHA1: "ad82bf6a9043ceed055076d0fd39f5f186ff8062"},
+		{Name: "large", Filename: "ggml-large-v3.bin", Size: "2.9 GB", SHA1: "ad82bf6a9043ceed055076d0fd39f5f186ff8062"},
+		{Name: "large-v3-turbo", Filename: "ggml-large-v3-turbo.bin", Size: "1.5 GB", SHA1: "4af2b29d7ec73d781377bfd1758ca957a807e941"},
+
+		// Quantized variants trade a little accuracy for less memory and
+		// faster inference.
+		{Name: "tiny-q5_1", Filename: "ggml-tiny-q5_1.bin", Size: "31 MB", SHA1: "2827a03e495b1ed3048ef28a6a4620537db4ee51"},
+		{Name: "tiny.en-q5_1", Filename: "ggml-tiny.en-q5_1.bin", Size: "31 MB", SHA1: "3fb92ec865cbbc769f08137f22470d6b66e071b6"},
+		{Name: "tiny-q8_0", Filename: "ggml-tiny-q8_0.bin", Size: "42 MB", SHA1: "19e8118f6652a650569f5a949d962154e01571d9"},
+		{Name: "base-q5_1", Filename: "ggml-base-q5_1.bin", Size: "57 MB", SHA1: "a3733eda680ef76256db5fc5dd9de8629e62c5e7"},
+		{Name: "base.en-q5_1", Filename: "ggml-base.en-q5_1.bin", Size: "57 MB", SHA1: "d26d7ce5a1b6e57bea5d0431b9c20ae49423c94a"},
+		{Name: "base-q8_0", Filename: "ggml-base-q8_0.bin", Size: "78 MB", SHA1: "7bb89bb49ed6955013b166f1b6a6c04584a20fbe"},
+		{Name: "small-q5_1", Filename: "ggml-small-q5_1.bin", Size: "181 MB", SHA1: "6fe57ddcfdd1c6b07cdcc73aaf620810ce5fc771"},
+		{Name: "small.en-q5_1", Filename: "ggml-small.en-q5_1.bin", Size: "181 MB", SHA1: "20f54878d608f94e4a8ee3ae56016571d47cba34"},
+		{Name: "small-q8_0", Filename: "ggml-small-q8_0.bin", Size: "252 MB", SHA1: "bcad8a2083f4e53d648d586b7dbc0cd673d8afad"},
+		{Name: "medium-q5_0", Filename: "ggml-medium-q5_0.bin", Size: "514 MB", SHA1: "7718d4c1ec62ca96998f058114db04036ec9c0e8"},
+		{Name: "medium.en-q5_0", Filename: "ggml-medium.en-q5_0.bin", Size: "514 MB", SHA1: "bb3b5281bddd61605d6fc76bc5b92d8f20284c3b"},
+		{Name: "medium-q8_0", Filename: "ggml-medium-q8_0.bin", Size: "785 MB", SHA1: "e66645948aff4bebbec71b3485c576f3d63af5d6"},
+		{Name: "large-v2-q5_0", Filename: "ggml-large-v2-q5_0.bin", Size: "1.1 GB", SHA1: "00e39f2196344e901b3a2bd5814807a769bd1630"},
+		{Name: "large-v2-q8_0", Filename: "ggml-large-v2-q8_0.bin", Size: "1.5 GB", SHA1: "da97d6ca8f8ffbeeb5fd147f79010eeea194ba38"},
+		{Name: "large-v3-q5_0", Filename: "ggml-large-v3-q5_0.bin", Size: "1.1 GB", SHA1: "e6e2ed78495d403bef4b7cff42ef4aaadcfea8de"},
+		{Name: "large-v3-turbo-q5_0", Filename: "ggml-large-v3-turbo-q5_0.bin", Size: "547 MB", SHA1: "e050f7970618a659205450ad97eb95a18d69c9ee"},
+		{Name: "large-v3-turbo-q8_0", Filename: "ggml-large-v3-turbo-q8_0.bin", Size: "834 MB", SHA1: "01bf15bedffe9f39d65c1b6ff9b687ea91f59e0e"},
 	}
 }
 
@@ -86,7 +113,9 @@ func ModelExists(name string) bool {
 	return err == nil
 }
 
-// Download downloads a model from Hugging Face.
+// Download downloads a model from Hugging Face. An interrupted download
+// resumes from the partial file on the next call, and the finished file is
+// verified against the model's checksum before it is installed.
 func Download(name string, onProgress ProgressFunc) error {
 	info, err := GetModelInfo(name)
 	if err != nil {
@@ -98,47 +127,89 @@ func Download(name string, onProgress ProgressFunc) error {
 		return fmt.Errorf("create models directory: %w", err)
 	}
 
-	destPath := filepath.Join(modelsDir, info.Filename)
+	return downloadFile(info, filepath.Join(modelsDir, info.Filename), onProgress)
+}
+
+// downloadFile fetches info.URL into destPath via destPath.tmp, resuming
+// from an existing partial file with an HTTP Range request.
+func downloadFile(info *ModelInfo, destPath string, onProgress ProgressFunc) error {
 	tmpPath := destPath + ".tmp"
 
-	// Create temporary file
-	out, err := os.Create(tmpPath)
+	// Keep the partial file on failure so the next attempt can resume.
+	out, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
 		return fmt.Errorf("create temp file: %w", err)
 	}
-	defer func() {
-		out.Close()
-		os.Remove(tmpPath) // Clean up on error
-	}()
+	defer out.Close()
 
-	// Start download
-	resp, err := http.Get(info.URL)
+	offset, err := out.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("open temp file: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, info.URL, nil)
+	if err != nil {
+		return fmt.Errorf("download request: %w", err)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("download request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	var totalSize int64
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		start, total, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		if !ok || start != offset {
+			return fmt.Errorf("download failed: unexpected Content-Range %q", resp.Header.Get("Content-Range"))
+		}
+		totalSize = total
+	case http.StatusOK:
+		// The server ignored the range; start over.
+		if err := out.Truncate(0); err != nil {
+			return fmt.Errorf("reset temp file: %w", err)
+		}
+		if _, err := out.Seek(0, io.SeekStart); err != nil {
+			return fmt.Errorf("reset temp file: %w", err)
+		}
+		offset = 0
+		totalSize = resp.ContentLength
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The partial file is already complete.
+		totalSize = offset
+	default:
 		return fmt.Errorf("download failed: HTTP %d", resp.StatusCode)
 	}
 
-	totalSize := resp.ContentLength
-
-	// Create progress reader
-	reader := &progressReader{
-		reader:     resp.Body,
-		total:      totalSize,
-		onProgress: onProgress,
+	if resp.StatusCode != http.StatusRequestedRangeNotSatisfiable {
+		reader := &progressReader{
+			reader:     resp.Body,
+			total:      totalSize,
+			downloaded: offset,
+			onProgress: onProgress,
+		}
+		if _, err := io.Copy(out, reader); err != nil {
+			return fmt.Errorf("download write: %w", err)
+		}
 	}
 
-	// Copy with progress
-	_, err = io.Copy(out, reader)
-	if err != nil {
+	// Close file before verifying and renaming
+	if err := out.Close(); err != nil {
 		return fmt.Errorf("download write: %w", err)
 	}
 
-	// Close file before rename
-	out.Close()
+	if err := VerifyFile(tmpPath, info); err != nil {
+		var sumErr *ChecksumError
+		if errors.As(err, &sumErr) {
+			sumErr.Quarantined = quarantine(tmpPath)
+		}
+		return err
+	}
 
 	// Rename temp file to final name
 	if err := os.Rename(tmpPath, destPath); err != nil {
@@ -148,6 +219,19 @@ func Download(name string, onProgress ProgressFunc) error {
 	return nil
 }
 
+// parseContentRange parses "bytes start-end/total". Total is -1 if the
+// server doesn't know it.
+func parseContentRange(header string) (start, total int64, ok bool) {
+	var end int64
+	if _, err := fmt.Sscanf(header, "bytes %d-%d/%d", &start, &end, &total); err == nil {
+		return start, total, true
+	}
+	if _, err := fmt.Sscanf(header, "bytes %d-%d/*", &start, &end); err == nil {
+		return start, -1, true
+	}
+	return 0, 0, false
+}
+
 // progressReader wraps an io.Reader to report progress.
 type progressReader struct {
 	reader     io.Reader
@@ -0,0 +1,196 @@
+package models
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var modelData = bytes.Repeat([]byte("ggml model weights "), 5000)
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func sha1Hex(b []byte) string {
+	sum := sha1.Sum(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// rangeServer serves modelData with Range support and records the Range
+// header of each request.
+func rangeServer(t *testing.T, ranges *[]string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*ranges = append(*ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "model.bin", time.Time{}, bytes.NewReader(modelData))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestDownloadFileVerifies(t *testing.T) {
+	var ranges []string
+	srv := rangeServer(t, &ranges)
+	dest := filepath.Join(t.TempDir(), "ggml-test.bin")
+
+	var last int64
+	info := &ModelInfo{Name: "test", URL: srv.URL, SHA256: sha256Hex(modelData)}
+	err := downloadFile(info, dest, func(downloaded, total int64) {
+		last = downloaded
+		if total != int64(len(modelData)) {
+			t.Errorf("total = %d, want %d", total, len(modelData))
+		}
+	})
+	if err != nil {
+		t.Fatalf("downloadFile: %v", err)
+	}
+
+	got, err := os.ReadFile(dest)
+	if err != nil || !bytes.Equal(got, modelData) {
+		t.Fatalf("installed file differs (err %v)", err)
+	}
+	if _, err := os.Stat(dest + ".tmp"); !os.IsNotExist(err) {
+		t.Error("temp file left behind")
+	}
+	if last != int64(len(modelData)) {
+		t.Errorf("last progress = %d, want %d", last, len(modelData))
+	}
+	if ranges[0] != "" {
+		t.Errorf("fresh download sent Range %q", ranges[0])
+	}
+}
+
+func TestDownloadFileResumes(t *testing.T) {
+	var ranges []string
+	srv := rangeServer(t, &ranges)
+	dest := filepath.Join(t.TempDir(), "ggml-test.bin")
+
+	half := len(modelData) / 2
+	if err := os.WriteFile(dest+".tmp", modelData[:half], 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var first int64 = -1
+	info := &ModelInfo{Name: "test", URL: srv.URL, SHA1: sha1Hex(modelData)}
+	err := downloadFile(info, dest, func(downloaded, total int64) {
+		if first < 0 {
+			first = downloaded
+		}
+	})
+	if err != nil {
+		t.Fatalf("downloadFile: %v", err)
+	}
+
+	if want := "bytes=" + strconv.Itoa(half) + "-"; ranges[0] != want {
+		t.Errorf("Range = %q, want %q", ranges[0], want)
+	}
+	if first < int64(half) {
+		t.Errorf("progress restarted from %d instead of %d", first, half)
+	}
+	got, _ := os.ReadFile(dest)
+	if !bytes.Equal(got, modelData) {
+		t.Error("resumed file differs")
+	}
+}
+
+func TestDownloadFileAfterInterruption(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			// Promise the whole file but drop the connection halfway.
+			w.Header().Set("Content-Length", strconv.Itoa(len(modelData)))
+			w.Write(modelData[:len(modelData)/3])
+			return
+		}
+		http.ServeContent(w, r, "model.bin", time.Time{}, bytes.NewReader(modelData))
+	}))
+	defer srv.Close()
+
+	dest := filepath.Join(t.TempDir(), "ggml-test.bin")
+	info := &ModelInfo{Name: "test", URL: srv.URL, SHA256: sha256Hex(modelData)}
+
+	if err := downloadFile(info, dest, nil); err == nil {
+		t.Fatal("expected error from interrupted download")
+	}
+	partial, err := os.Stat(dest + ".tmp")
+	if err != nil || partial.Size() == 0 {
+		t.Fatalf("partial file not kept: %v", err)
+	}
+
+	if err := downloadFile(info, dest, nil); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	got, _ := os.ReadFile(dest)
+	if !bytes.Equal(got, modelData) {
+		t.Error("resumed file differs")
+	}
+}
+
+func TestDownloadFileRestartsWithoutRangeSupport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(modelData)
+	}))
+	defer srv.Close()
+
+	dest := filepath.Join(t.TempDir(), "ggml-test.bin")
+	os.WriteFile(dest+".tmp", []byte("stale partial data"), 0644)
+
+	info := &ModelInfo{Name: "test", URL: srv.URL, SHA256: sha256Hex(modelData)}
+	if err := downloadFile(info, dest, nil); err != nil {
+		t.Fatalf("downloadFile: %v", err)
+	}
+	got, _ := os.ReadFile(dest)
+	if !bytes.Equal(got, modelData) {
+		t.Error("file not restarted from scratch")
+	}
+}
+
+func TestDownloadFileQuarantinesCorrupt(t *testing.T) {
+	var ranges []string
+	srv := rangeServer(t, &ranges)
+	dir := t.TempDir()
+	dest := filepath.Join(dir, "ggml-test.bin")
+
+	info := &ModelInfo{Name: "test", URL: srv.URL, SHA256: strings.Repeat("0", 64)}
+	err := downloadFile(info, dest, nil)
+
+	var sumErr *ChecksumError
+	if !errors.As(err, &sumErr) {
+		t.Fatalf("expected ChecksumError, got %v", err)
+	}
+	if sumErr.Actual != sha256Hex(modelData) {
+		t.Errorf("Actual = %s", sumErr.Actual)
+	}
+	if _, err := os.Stat(dest); !os.IsNotExist(err) {
+		t.Error("corrupt model was installed")
+	}
+	if _, err := os.Stat(dest + ".tmp"); !os.IsNotExist(err) {
+		t.Error("corrupt file left to be resumed")
+	}
+	if sumErr.Quarantined == "" {
+		t.Fatal("corrupt file not quarantined")
+	}
+	if _, err := os.Stat(sumErr.Quarantined); err != nil {
+		t.Errorf("quarantined file missing: %v", err)
+	}
+}
+
+func TestBuiltinModelsHaveChecksums(t *testing.T) {
+	for _, m := range AvailableModels() {
+		if !m.HasChecksum() {
+			t.Errorf("model %s has no checksum", m.Name)
+		}
+	}
+}
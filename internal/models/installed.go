@@ -0,0 +1,132 @@
+package models
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// InstalledFile is a model file found in one of the search paths.
+type InstalledFile struct {
+	Path string
+	Dir  string
+	Size int64
+	// Models lists the registered models stored in this file; several
+	// names can share one file, e.g. large and large-v3.
+	Models []ModelInfo
+}
+
+// SearchPaths returns the directories searched for model files, in order
+// of preference. The download directory comes first after an explicit
+// WHISPER_MODEL_PATH.
+func SearchPaths() []string {
+	home, _ := os.UserHomeDir()
+	candidates := []string{
+		os.Getenv("WHISPER_MODEL_PATH"),
+		GetModelsDir(),
+	}
+	if home != "" {
+		candidates = append(candidates,
+			filepath.Join(home, ".whisper", "models"),
+			filepath.Join(home, ".cache", "whisper"),
+		)
+	}
+	candidates = append(candidates,
+		"/usr/share/whisper/models",
+		"/usr/local/share/whisper/models",
+	)
+
+	seen := make(map[string]bool)
+	var paths []string
+	for _, p := range candidates {
+		if p == "" {
+			continue
+		}
+		p = filepath.Clean(p)
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+		paths = append(paths, p)
+	}
+	return paths
+}
+
+// CandidateFilenames returns the file names a model may be stored under,
+// starting with its registered file name. If english is set, the job can be
+// run by the English-only variant of the model, which is a fallback.
+func CandidateFilenames(name string, english bool) []string {
+	var names []string
+	if info, err := GetModelInfo(name); err == nil {
+		names = append(names, info.Filename)
+	}
+	candidates := []string{fmt.Sprintf("ggml-%s.bin", name)}
+	if english {
+		candidates = append(candidates, fmt.Sprintf("ggml-%s.en.bin", name))
+	}
+	candidates = append(candidates,
+		fmt.Sprintf("%s.bin", name),
+		fmt.Sprintf("ggml-model-%s.bin", name),
+	)
+	for _, n := range candidates {
+		if len(names) == 0 || n != names[0] {
+			names = append(names, n)
+		}
+	}
+	return names
+}
+
+// FindInstalled lists the model files in every search path. Files are
+// sorted by directory, in search order, then by name.
+func FindInstalled() ([]InstalledFile, error) {
+	byFilename := make(map[string][]ModelInfo)
+	for _, m := range AvailableModels() {
+		byFilename[m.Filename] = append(byFilename[m.Filename], m)
+	}
+
+	var files []InstalledFile
+	for _, dir := range SearchPaths() {
+		entries, err := os.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", dir, err)
+		}
+
+		var found []InstalledFile
+		for _, e := range entries {
+			if e.IsDir() || !strings.HasSuffix(e.Name(), ".bin") {
+				continue
+			}
+			info, err := e.Info()
+			if err != nil {
+				continue
+			}
+			found = append(found, InstalledFile{
+				Path:   filepath.Join(dir, e.Name()),
+				Dir:    dir,
+				Size:   info.Size(),
+				Models: byFilename[e.Name()],
+			})
+		}
+		sort.Slice(found, func(i, j int) bool { return found[i].Path < found[j].Path })
+		files = append(files, found...)
+	}
+	return files, nil
+}
+
+// Names returns the registered model names of the file, or its file name
+// if it isn't a registered model.
+func (f *InstalledFile) Names() string {
+	if len(f.Models) == 0 {
+		return filepath.Base(f.Path)
+	}
+	names := make([]string, len(f.Models))
+	for i, m := range f.Models {
+		names[i] = m.Name
+	}
+	return strings.Join(names, ", ")
+}
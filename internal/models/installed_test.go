@@ -0,0 +1,83 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestFindInstalled(t *testing.T) {
+	home := t.TempDir()
+	modelDir := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("WHISPER_MODEL_PATH", modelDir)
+
+	otherDir := filepath.Join(home, ".whisper", "models")
+	os.MkdirAll(otherDir, 0755)
+	os.WriteFile(filepath.Join(modelDir, "ggml-base.bin"), []byte("base"), 0644)
+	os.WriteFile(filepath.Join(modelDir, "ggml-large-v3.bin"), []byte("large"), 0644)
+	os.WriteFile(filepath.Join(modelDir, "notes.txt"), []byte("ignored"), 0644)
+	os.WriteFile(filepath.Join(otherDir, "ggml-base.bin"), []byte("base"), 0644)
+	os.WriteFile(filepath.Join(otherDir, "ggml-custom.bin"), []byte("custom"), 0644)
+
+	paths := SearchPaths()
+	if paths[0] != modelDir {
+		t.Errorf("first search path = %s, want %s", paths[0], modelDir)
+	}
+	seen := make(map[string]bool)
+	for _, p := range paths {
+		if seen[p] {
+			t.Errorf("search path %s listed twice", p)
+		}
+		seen[p] = true
+	}
+
+	files, err := FindInstalled()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]string)
+	for _, f := range files {
+		got[f.Path] = f.Names()
+	}
+	want := map[string]string{
+		filepath.Join(modelDir, "ggml-base.bin"):     "base",
+		filepath.Join(modelDir, "ggml-large-v3.bin"): "large-v3, large",
+		filepath.Join(otherDir, "ggml-base.bin"):     "base",
+		filepath.Join(otherDir, "ggml-custom.bin"):   "ggml-custom.bin",
+	}
+	if len(got) != len(want) {
+		t.Errorf("found %v, want %v", got, want)
+	}
+	for path, names := range want {
+		if got[path] != names {
+			t.Errorf("%s: names = %q, want %q", path, got[path], names)
+		}
+	}
+	if files[0].Dir != modelDir {
+		t.Errorf("files not in search order: first is in %s", files[0].Dir)
+	}
+}
+
+func TestCandidateFilenames(t *testing.T) {
+	names := CandidateFilenames("large", false)
+	if names[0] != "ggml-large-v3.bin" {
+		t.Errorf("registered file name should come first, got %v", names)
+	}
+	names = CandidateFilenames("base", false)
+	for i, n := range names[1:] {
+		if n == names[0] {
+			t.Errorf("duplicate candidate %s at %d", n, i+1)
+		}
+	}
+
+	// An English-only model can't stand in for other languages.
+	if slices.Contains(names, "ggml-base.en.bin") {
+		t.Errorf("English-only fallback without English speech: %v", names)
+	}
+	if names := CandidateFilenames("base", true); !slices.Contains(names, "ggml-base.en.bin") {
+		t.Errorf("missing English-only fallback: %v", names)
+	}
+}
@@ -0,0 +1,166 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	registryMu  sync.RWMutex
+	baseURL     string
+	extraModels []ModelInfo
+)
+
+// registryFile is the format of a model registry file. JSON files are read
+// the same way, since JSON is valid YAML.
+type registryFile struct {
+	Models []struct {
+		Name     string `yaml:"name"`
+		Filename string `yaml:"filename"`
+		Size     string `yaml:"size"`
+		// URL is optional; relative URLs and entries without one are
+		// resolved against the base URL.
+		URL    string `yaml:"url"`
+		SHA1   string `yaml:"sha1"`
+		SHA256 string `yaml:"sha256"`
+	} `yaml:"models"`
+}
+
+// SetBaseURL sets the mirror models are downloaded from. An empty URL
+// restores the Hugging Face default.
+func SetBaseURL(url string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	baseURL = strings.TrimSuffix(strings.TrimSpace(url), "/")
+}
+
+// BaseURL returns the download base URL. WHISPER_MODEL_MIRROR takes
+// precedence over SetBaseURL.
+func BaseURL() string {
+	if url := os.Getenv("WHISPER_MODEL_MIRROR"); url != "" {
+		return strings.TrimSuffix(url, "/")
+	}
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	if baseURL != "" {
+		return baseURL
+	}
+	return HuggingFaceBaseURL
+}
+
+// DefaultRegistryPath returns the registry file read when none is
+// configured.
+func DefaultRegistryPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "whisper-transcribe", "models.yaml")
+}
+
+// LoadRegistry reads additional models from a YAML or JSON file, replacing
+// any previously loaded ones. Entries with the name of a built-in model
+// override it.
+func LoadRegistry(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read model registry: %w", err)
+	}
+
+	var file registryFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("parse model registry %s: %w", path, err)
+	}
+
+	var loaded []ModelInfo
+	seen := make(map[string]bool)
+	for i, m := range file.Models {
+		if m.Name == "" {
+			return fmt.Errorf("model registry %s: entry %d has no name", path, i+1)
+		}
+		if seen[m.Name] {
+			return fmt.Errorf("model registry %s: duplicate model %q", path, m.Name)
+		}
+		seen[m.Name] = true
+
+		filename := m.Filename
+		if filename == "" {
+			filename = "ggml-" + m.Name + ".bin"
+		}
+		if filepath.Base(filename) != filename {
+			return fmt.Errorf("model registry %s: filename %q of %q must not contain a directory", path, filename, m.Name)
+		}
+		loaded = append(loaded, ModelInfo{
+			Name:     m.Name,
+			Filename: filename,
+			Size:     m.Size,
+			URL:      m.URL,
+			SHA1:     m.SHA1,
+			SHA256:   m.SHA256,
+		})
+	}
+
+	registryMu.Lock()
+	extraModels = loaded
+	registryMu.Unlock()
+	return nil
+}
+
+// LoadDefaultRegistry loads the registry at DefaultRegistryPath if it
+// exists.
+func LoadDefaultRegistry() error {
+	path := DefaultRegistryPath()
+	if path == "" {
+		return nil
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return LoadRegistry(path)
+}
+
+// AvailableModels returns information about all available models: the
+// built-in whisper.cpp models merged with those from the registry file.
+func AvailableModels() []ModelInfo {
+	registryMu.RLock()
+	extras := extraModels
+	registryMu.RUnlock()
+
+	all := builtinModels()
+	for _, extra := range extras {
+		replaced := false
+		for i := range all {
+			if all[i].Name == extra.Name {
+				all[i] = extra
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			all = append(all, extra)
+		}
+	}
+
+	base := BaseURL()
+	for i := range all {
+		all[i].URL = resolveURL(base, all[i])
+	}
+	return all
+}
+
+func resolveURL(base string, m ModelInfo) string {
+	switch {
+	case m.URL == "":
+		return base + "/" + m.Filename
+	case strings.Contains(m.URL, "://"):
+		return m.URL
+	default:
+		return base + "/" + strings.TrimPrefix(m.URL, "/")
+	}
+}
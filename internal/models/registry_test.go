@@ -0,0 +1,88 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Cleanup(func() {
+		SetBaseURL("")
+		extraModels = nil
+	})
+	t.Setenv("WHISPER_MODEL_MIRROR", "")
+
+	path := filepath.Join(t.TempDir(), "models.yaml")
+	os.WriteFile(path, []byte(`
+models:
+  - name: large-v3-q5_0
+    filename: ggml-large-v3-q5_0.bin
+    size: 1.1 GB
+    sha256: 64d182b440b98d5203c4f9bd541544d84c605196c4f7b845dfa11fb23594d1e2
+  - name: inhouse
+    url: https://models.example.com/inhouse/v2.bin
+  - name: base
+    filename: ggml-base.bin
+    size: 142 MB
+    url: mirror/base.bin
+`), 0644)
+
+	SetBaseURL("https://artifacts.internal/whisper/")
+	if err := LoadRegistry(path); err != nil {
+		t.Fatalf("LoadRegistry: %v", err)
+	}
+
+	byName := make(map[string]ModelInfo)
+	for _, m := range AvailableModels() {
+		if _, dup := byName[m.Name]; dup {
+			t.Errorf("model %s listed twice", m.Name)
+		}
+		byName[m.Name] = m
+	}
+
+	q5 := byName["large-v3-q5_0"]
+	if q5.URL != "https://artifacts.internal/whisper/ggml-large-v3-q5_0.bin" {
+		t.Errorf("q5 URL = %s", q5.URL)
+	}
+	if !q5.HasChecksum() {
+		t.Error("q5 checksum not loaded")
+	}
+	if got := byName["inhouse"]; got.Filename != "ggml-inhouse.bin" || got.URL != "https://models.example.com/inhouse/v2.bin" {
+		t.Errorf("inhouse = %+v", got)
+	}
+	if got := byName["base"]; got.URL != "https://artifacts.internal/whisper/mirror/base.bin" || got.HasChecksum() {
+		t.Errorf("base override = %+v", got)
+	}
+	if got := byName["tiny"]; got.URL != "https://artifacts.internal/whisper/ggml-tiny.bin" {
+		t.Errorf("built-in tiny URL = %s", got.URL)
+	}
+
+	t.Setenv("WHISPER_MODEL_MIRROR", "http://localhost:8080/models")
+	if info, _ := GetModelInfo("tiny"); !strings.HasPrefix(info.URL, "http://localhost:8080/models/") {
+		t.Errorf("env mirror not used: %s", info.URL)
+	}
+}
+
+func TestLoadRegistryErrors(t *testing.T) {
+	t.Cleanup(func() { extraModels = nil })
+	dir := t.TempDir()
+
+	cases := map[string]string{
+		"noname.json":    `{"models": [{"filename": "x.bin"}]}`,
+		"duplicate.yaml": "models:\n  - name: a\n  - name: a\n",
+		"dir.yaml":       "models:\n  - name: a\n    filename: ../a.bin\n",
+		"invalid.yaml":   "models: [",
+	}
+	for name, content := range cases {
+		path := filepath.Join(dir, name)
+		os.WriteFile(path, []byte(content), 0644)
+		if err := LoadRegistry(path); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	if err := LoadRegistry(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("missing file: expected error")
+	}
+}
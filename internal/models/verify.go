@@ -0,0 +1,77 @@
+package models
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// ChecksumError reports a model file that doesn't match its checksum.
+type ChecksumError struct {
+	Path     string
+	Expected string
+	Actual   string
+	// Quarantined is where the corrupt file was moved, if it was.
+	Quarantined string
+}
+
+func (e *ChecksumError) Error() string {
+	msg := fmt.Sprintf("checksum mismatch for %s: expected %s, got %s", e.Path, e.Expected, e.Actual)
+	if e.Quarantined != "" {
+		msg += " (moved to " + e.Quarantined + ")"
+	}
+	return msg
+}
+
+// HasChecksum reports whether the model can be verified.
+func (m *ModelInfo) HasChecksum() bool {
+	return m.SHA256 != "" || m.SHA1 != ""
+}
+
+// VerifyFile checks path against the model's checksum, preferring SHA-256.
+// Models without a known checksum always pass.
+func VerifyFile(path string, info *ModelInfo) error {
+	var h hash.Hash
+	var expected string
+	switch {
+	case info.SHA256 != "":
+		h, expected = sha256.New(), info.SHA256
+	case info.SHA1 != "":
+		h, expected = sha1.New(), info.SHA1
+	default:
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open model: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(h, f); err != nil {
+		return fmt.Errorf("read model: %w", err)
+	}
+
+	actual := hex.EncodeToString(h.Sum(nil))
+	if !strings.EqualFold(actual, expected) {
+		return &ChecksumError{Path: path, Expected: expected, Actual: actual}
+	}
+	return nil
+}
+
+// quarantine moves a corrupt file aside so it is neither used nor resumed,
+// returning its new path, or "" if it had to be removed instead.
+func quarantine(path string) string {
+	dest := fmt.Sprintf("%s.corrupt-%s", strings.TrimSuffix(path, ".tmp"), time.Now().Format("20060102-150405"))
+	if err := os.Rename(path, dest); err != nil {
+		os.Remove(path)
+		return ""
+	}
+	return dest
+}
@@ -0,0 +1,49 @@
+package pipeline
+
+import "context"
+
+// Limiter bounds how many pipelines may run a step at the same time.
+// A nil Limiter places no bound.
+type Limiter struct {
+	slots chan struct{}
+}
+
+// NewLimiter creates a limiter allowing n concurrent holders. Values below
+// one are treated as one.
+func NewLimiter(n int) *Limiter {
+	return &Limiter{slots: make(chan struct{}, max(n, 1))}
+}
+
+// TryAcquire takes a slot if one is free without waiting.
+func (l *Limiter) TryAcquire() bool {
+	if l == nil {
+		return true
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// Acquire waits for a free slot or for ctx to be cancelled.
+func (l *Limiter) Acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release frees a slot taken by Acquire or TryAcquire.
+func (l *Limiter) Release() {
+	if l == nil {
+		return
+	}
+	<-l.slots
+}
@@ -0,0 +1,24 @@
+package pipeline
+
+import "testing"
+
+func TestLimiter(t *testing.T) {
+	l := NewLimiter(2)
+	if !l.TryAcquire() || !l.TryAcquire() {
+		t.Fatal("expected two free slots")
+	}
+	if l.TryAcquire() {
+		t.Fatal("acquired a third slot")
+	}
+	l.Release()
+	if !l.TryAcquire() {
+		t.Fatal("released slot not reusable")
+	}
+
+	var unbounded *Limiter
+	for i := 0; i < 10; i++ {
+		if !unbounded.TryAcquire() {
+			t.Fatal("nil limiter should never block")
+		}
+	}
+}
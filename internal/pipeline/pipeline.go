@@ -2,14 +2,20 @@ package pipeline
 
 import (
 	"context"
+	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/cyber/whisper-transcribe/internal/audio"
+	"github.com/cyber/whisper-transcribe/internal/cache"
 	"github.com/cyber/whisper-transcribe/internal/config"
 	"github.com/cyber/whisper-transcribe/internal/downloader"
 	"github.com/cyber/whisper-transcribe/internal/formatter"
+	"github.com/cyber/whisper-transcribe/internal/history"
 	"github.com/cyber/whisper-transcribe/internal/transcriber"
+	"github.com/cyber/whisper-transcribe/internal/workspace"
 )
 
 // Event represents a pipeline event.
@@ -45,8 +51,9 @@ func (TranscriptEvent) isEvent() {}
 
 // CompletedEvent signals successful completion.
 type CompletedEvent struct {
-	OutputPath string
-	Stats      Stats
+	OutputPath  string
+	OutputPaths []string
+	Stats       Stats
 }
 
 func (CompletedEvent) isEvent() {}
@@ -59,11 +66,22 @@ type ErrorEvent struct {
 
 func (ErrorEvent) isEvent() {}
 
+// CancelledEvent signals that the pipeline was cancelled during Step. Its
+// temporary files and any output it had written are removed.
+type CancelledEvent struct {
+	Step string
+}
+
+func (CancelledEvent) isEvent() {}
+
 // Stats holds transcription statistics.
 type Stats struct {
 	Duration  string
 	WordCount int
 	Model     string
+	// WER compares whisper against the video's captions, if both were
+	// requested and captions exist.
+	WER *transcriber.WER
 }
 
 // Pipeline orchestrates the transcription workflow.
@@ -72,6 +90,11 @@ type Pipeline struct {
 	events chan<- Event
 	ctx    context.Context
 	cancel context.CancelFunc
+
+	downloadLimit   *Limiter
+	transcribeLimit *Limiter
+	history         *history.Store
+	cache           *cache.Cache
 }
 
 // New creates a new pipeline.
@@ -85,15 +108,71 @@ func New(cfg *config.TranscriptionConfig, events chan<- Event) *Pipeline {
 	}
 }
 
+// SetLimiters shares download and transcription limits with other
+// pipelines. Either limiter may be nil for no bound.
+func (p *Pipeline) SetLimiters(download, transcribe *Limiter) {
+	p.downloadLimit = download
+	p.transcribeLimit = transcribe
+}
+
+// SetHistory records completed jobs in s. A nil store disables recording.
+func (p *Pipeline) SetHistory(s *history.Store) {
+	p.history = s
+}
+
+// SetCache reuses and stores transcriptions in c. A nil cache disables
+// caching.
+func (p *Pipeline) SetCache(c *cache.Cache) {
+	p.cache = c
+}
+
+// acquire takes a slot from l, reporting on step while it has to wait.
+func (p *Pipeline) acquire(l *Limiter, step, message string) error {
+	if l.TryAcquire() {
+		return nil
+	}
+	p.events <- ProgressEvent{Step: step, Progress: 0, Message: message}
+	return l.Acquire(p.ctx)
+}
+
 // Run executes the pipeline steps.
 func (p *Pipeline) Run() {
 	defer p.cancel()
 
+	startedAt := time.Now()
+	local := p.config.IsLocalFile()
 	var meta *downloader.Metadata
-	var audioPath string
+	var audioPath, downloaded string
 	var err error
 
-	if p.config.IsLocalFile() {
+	// Downloads and intermediate audio live in the job's own workspace,
+	// which is removed however the job ends.
+	ws, err := workspace.New()
+	if err != nil {
+		p.fail("metadata", err)
+		return
+	}
+	defer ws.Remove()
+
+	var vocab *formatter.Vocabulary
+	if p.config.Vocabulary != "" {
+		if vocab, err = formatter.LoadVocabulary(p.config.Vocabulary); err != nil {
+			p.fail("glossary", err)
+			return
+		}
+	}
+	var turns []transcriber.SpeakerTurn
+	if p.config.RTTM != "" {
+		if turns, err = transcriber.LoadRTTM(p.config.RTTM); err != nil {
+			p.fail("transcribe", err)
+			return
+		}
+	}
+	opts := p.config.TranscribeOptions()
+	opts.TempDir = ws.Dir
+	opts.Whisper.Prompt = vocab.Prompt(opts.Whisper.Prompt)
+
+	if local {
 		// Local file: create metadata from filename
 		meta = createLocalMetadata(p.config.LocalFile)
 		audioPath = p.config.LocalFile
@@ -105,11 +184,17 @@ func (p *Pipeline) Run() {
 		}
 		p.events <- ProgressEvent{Step: "metadata", Progress: 1.0, Message: "Local file ready"}
 	} else {
+		if err := p.acquire(p.downloadLimit, "metadata", "Waiting for a download slot..."); err != nil {
+			p.fail("metadata", err)
+			return
+		}
+
 		// Step 1: Fetch metadata
 		p.events <- ProgressEvent{Step: "metadata", Progress: 0, Message: "Fetching video info..."}
 		meta, err = downloader.FetchMetadata(p.ctx, p.config.URL)
 		if err != nil {
-			p.events <- ErrorEvent{Step: "metadata", Err: err}
+			p.downloadLimit.Release()
+			p.fail("metadata", err)
 			return
 		}
 		p.events <- MetadataEvent{
@@ -118,72 +203,390 @@ func (p *Pipeline) Run() {
 			Duration: meta.Duration,
 		}
 		p.events <- ProgressEvent{Step: "metadata", Progress: 1.0, Message: "Done"}
+	}
+
+	var captions []transcriber.Segment
+	var captionLang string
+	if !local && (p.config.Captions == config.CaptionsPrefer || p.config.Captions == config.CaptionsBoth) {
+		captions, captionLang = p.fetchCaptions(meta, ws.Dir)
+	}
 
-		// Step 2: Download audio
-		p.events <- ProgressEvent{Step: "download", Progress: 0, Message: "Starting download..."}
-		audioPath, err = downloader.Download(p.ctx, p.config.URL, func(progress float64) {
-			p.events <- ProgressEvent{Step: "download", Progress: progress, Message: "Downloading..."}
+	// outCfg is the job as seen by the output writers, which name the
+	// model that produced the transcript.
+	outCfg := p.config
+	var key *cache.Key
+	var segments []transcriber.Segment
+	if captions != nil && p.config.Captions == config.CaptionsPrefer {
+		// Human-made captions replace whisper entirely.
+		segments = captions
+		withCaptions := *p.config
+		withCaptions.Model = config.CaptionsModel
+		outCfg = &withCaptions
+		p.downloadLimit.Release()
+		p.events <- ProgressEvent{Step: "download", Progress: 1.0, Message: "Using " + captionLang + " captions"}
+		p.events <- ProgressEvent{Step: "preprocess", Progress: 1.0, Message: "Skipped (captions)"}
+		p.events <- ProgressEvent{Step: "transcribe", Progress: 1.0, Message: "Skipped (captions)"}
+	} else if key, segments = p.lookupCache(meta, opts); segments != nil {
+		// Cache hit: only the output needs to be rendered again.
+		if !local {
+			p.downloadLimit.Release()
+			p.events <- ProgressEvent{Step: "download", Progress: 1.0, Message: "Cached"}
+		}
+		p.events <- ProgressEvent{Step: "preprocess", Progress: 1.0, Message: "Cached"}
+		p.events <- ProgressEvent{Step: "transcribe", Progress: 1.0, Message: "Cached"}
+	} else {
+		if !local {
+			// Step 2: Download audio
+			p.events <- ProgressEvent{Step: "download", Progress: 0, Message: "Starting download..."}
+			audioPath, err = downloader.Download(p.ctx, p.config.URL, ws.Dir, downloader.Options{
+				Stereo: p.config.Diarize == transcriber.DiarizeStereo,
+				Start:  p.config.Start,
+				End:    p.config.End,
+			}, func(progress float64) {
+				p.events <- ProgressEvent{Step: "download", Progress: progress, Message: "Downloading..."}
+			})
+			p.downloadLimit.Release()
+			if err != nil {
+				p.fail("download", err)
+				return
+			}
+			downloaded = audioPath
+			p.events <- ProgressEvent{Step: "download", Progress: 1.0, Message: "Done"}
+		}
+
+		// Step 3: Convert to whisper's format and apply audio filters
+		p.events <- ProgressEvent{Step: "preprocess", Progress: 0, Message: "Preparing audio..."}
+		prepared, err := audio.Prepare(p.ctx, audioPath, ws.Dir, p.config.AudioOptions(), func(progress float64) {
+			p.events <- ProgressEvent{Step: "preprocess", Progress: progress, Message: "Converting..."}
 		})
 		if err != nil {
-			p.events <- ErrorEvent{Step: "download", Err: err}
+			p.fail("preprocess", err)
 			return
 		}
-		p.events <- ProgressEvent{Step: "download", Progress: 1.0, Message: "Done"}
-	}
+		// Timestamps stay relative to the original media. Downloads are
+		// clipped by yt-dlp, so they start at the clip's start.
+		opts.Offset = prepared.Offset
+		if !local {
+			opts.Offset += p.config.Start
+		}
+		message := preprocessMessage(prepared)
+		chunks := []audio.Chunk{{Path: prepared.Path}}
+		if p.config.Chunked() {
+			if chunks, err = audio.Split(prepared.Path, ws.Dir, p.config.ChunkLength); err != nil {
+				p.fail("preprocess", err)
+				return
+			}
+			if len(chunks) > 1 {
+				message += fmt.Sprintf(", %d chunks", len(chunks))
+			}
+		}
+		p.events <- ProgressEvent{Step: "preprocess", Progress: 1.0, Message: message}
 
-	// Step 3: Transcribe
-	p.events <- ProgressEvent{Step: "transcribe", Progress: 0, Message: "Starting transcription..."}
-	segments, err := transcriber.Transcribe(p.ctx, audioPath, p.config.Model, func(chunk transcriber.Chunk) {
-		p.events <- TranscriptEvent{
-			Text:      chunk.Text,
-			Timestamp: chunk.Timestamp,
+		// Step 4: Transcribe
+		if err := p.acquire(p.transcribeLimit, "transcribe", "Waiting for a free worker..."); err != nil {
+			p.fail("transcribe", err)
+			return
 		}
-		p.events <- ProgressEvent{
-			Step:     "transcribe",
-			Progress: chunk.Progress,
-			Message:  "Transcribing...",
+		message = "Starting transcription..."
+		if p.config.Language != "en" && transcriber.EnglishOnlyFallback(opts.Model) {
+			// Existing installs may only have the English-only model.
+			message = fmt.Sprintf("Starting transcription as English (only %s.en is installed)...", opts.Model)
 		}
-	})
-	if err != nil {
-		p.events <- ErrorEvent{Step: "transcribe", Err: err}
-		return
+		p.events <- ProgressEvent{Step: "transcribe", Progress: 0, Message: message}
+		segments, err = transcriber.TranscribeChunks(p.ctx, chunks, opts, p.config.ChunkWorkers, p.transcribeLimit, func(chunk transcriber.Chunk) {
+			if chunk.Text != "" {
+				p.events <- TranscriptEvent{
+					Text:      chunk.Text,
+					Timestamp: chunk.Timestamp,
+				}
+			}
+			p.events <- ProgressEvent{
+				Step:     "transcribe",
+				Progress: chunk.Progress,
+				Message:  "Transcribing...",
+			}
+		})
+		p.transcribeLimit.Release()
+		if err != nil {
+			p.fail("transcribe", err)
+			return
+		}
+
+		message = "Done"
+		if err := p.storeCache(key, meta, segments); err != nil {
+			message = "Done (not cached: " + err.Error() + ")"
+		}
+		p.events <- ProgressEvent{Step: "transcribe", Progress: 1.0, Message: message}
+	}
+
+	// External speaker turns and names, like the glossary, are applied to
+	// the cached segments rather than stored with them.
+	if turns != nil {
+		segments = transcriber.AssignSpeakers(segments, turns)
+	}
+	segments = transcriber.RenameSpeakers(segments, p.config.Speakers)
+
+	// Step 5: Correct known misspellings. This runs after caching so that
+	// editing the glossary doesn't require transcribing again.
+	if vocab != nil {
+		p.events <- ProgressEvent{Step: "glossary", Progress: 0, Message: "Applying glossary..."}
+		var fixed int
+		segments, fixed = vocab.Apply(segments)
+		p.events <- ProgressEvent{Step: "glossary", Progress: 1.0, Message: fmt.Sprintf("%d corrections", fixed)}
+	} else {
+		p.events <- ProgressEvent{Step: "glossary", Progress: 1.0, Message: "No vocabulary"}
 	}
-	p.events <- ProgressEvent{Step: "transcribe", Progress: 1.0, Message: "Done"}
 
-	// Step 4: Format markdown
-	p.events <- ProgressEvent{Step: "format", Progress: 0, Message: "Generating markdown..."}
-	outputPath, err := formatter.GenerateMarkdown(meta, segments, p.config)
+	// Step 6: Format output
+	p.events <- ProgressEvent{Step: "format", Progress: 0, Message: "Generating output..."}
+	writers, err := formatter.WritersFor(p.config.Formats())
 	if err != nil {
-		p.events <- ErrorEvent{Step: "format", Err: err}
+		p.fail("format", err)
 		return
 	}
-	p.events <- ProgressEvent{Step: "format", Progress: 1.0, Message: "Done"}
+	var outputPaths []string
+	var markdownPaths []string
+	for i, w := range writers {
+		p.events <- ProgressEvent{
+			Step:     "format",
+			Progress: float64(i) / float64(len(writers)),
+			Message:  "Writing " + w.Name() + "...",
+		}
+		path, err := w.Write(meta, segments, outCfg)
+		if err != nil {
+			p.fail("format", fmt.Errorf("%s: %w", w.Name(), err))
+			return
+		}
+		outputPaths = append(outputPaths, path)
+		if w.Extension() == ".md" {
+			markdownPaths = append(markdownPaths, path)
+		}
+	}
+	if p.config.KeepAudio && downloaded != "" {
+		p.events <- ProgressEvent{Step: "format", Progress: 1.0, Message: "Keeping audio..."}
+		path := formatter.OutputPath(meta, outCfg, ".wav")
+		if err := workspace.Keep(downloaded, path); err != nil {
+			p.fail("format", err)
+			return
+		}
+		outputPaths = append(outputPaths, path)
+	}
+	formatMessage := "Done"
+	if p.config.KeepAudio && downloaded == "" && !local {
+		// Cached transcripts and captions need no download.
+		formatMessage = "Done (audio not kept: nothing was downloaded)"
+	}
+	p.events <- ProgressEvent{Step: "format", Progress: 1.0, Message: formatMessage}
 
-	// Step 5: Validate
+	// Step 7: Validate
 	p.events <- ProgressEvent{Step: "validate", Progress: 0, Message: "Checking markdown..."}
-	if err := formatter.LintMarkdown(outputPath); err != nil {
-		// Log warning but don't fail
+	warnings := false
+	for _, path := range markdownPaths {
+		if err := formatter.LintMarkdown(path); err != nil {
+			// Log warning but don't fail
+			warnings = true
+		}
+	}
+	if warnings {
 		p.events <- ProgressEvent{Step: "validate", Progress: 1.0, Message: "Warnings found"}
 	} else {
 		p.events <- ProgressEvent{Step: "validate", Progress: 1.0, Message: "Passed"}
 	}
 
-	// Complete
+	stats := Stats{
+		Duration:  meta.Duration,
+		WordCount: transcriber.CountWords(segments),
+		Model:     outCfg.Model,
+	}
+
+	if captions != nil && p.config.Captions == config.CaptionsBoth {
+		wer := transcriber.WordErrorRate(captions, segments)
+		stats.WER = &wer
+		path, err := formatter.WriteWERReport(meta, p.config, captionLang, wer)
+		if err != nil {
+			p.fail("validate", err)
+			return
+		}
+		outputPaths = append(outputPaths, path)
+		p.events <- ProgressEvent{
+			Step:     "validate",
+			Progress: 1.0,
+			Message:  fmt.Sprintf("WER vs %s captions: %.1f%%", captionLang, wer.Rate()*100),
+		}
+	}
+
+	// Writing the output isn't interruptible, so a job cancelled meanwhile
+	// removes it again rather than completing.
+	if p.ctx.Err() != nil {
+		removeOutputs(outputPaths)
+		p.fail("validate", p.ctx.Err())
+		return
+	}
+
+	if p.history != nil {
+		_, err := p.history.Add(history.Entry{
+			Source:      p.config.GetSource(),
+			Metadata:    *meta,
+			Model:       stats.Model,
+			OutputPaths: outputPaths,
+			WordCount:   stats.WordCount,
+			StartedAt:   startedAt,
+			CompletedAt: time.Now(),
+		})
+		if err != nil {
+			// The transcript is written; a history failure shouldn't fail the job.
+			p.events <- ProgressEvent{Step: "validate", Progress: 1.0, Message: "History not saved: " + err.Error()}
+		}
+	}
+
+	// Complete. The Markdown transcript is the one previewed, whatever the
+	// order of the formats.
 	p.events <- CompletedEvent{
-		OutputPath: outputPath,
-		Stats: Stats{
-			Duration:  meta.Duration,
-			WordCount: transcriber.CountWords(segments),
-			Model:     p.config.Model,
-		},
+		OutputPath:  formatter.PrimaryOutput(outputPaths),
+		OutputPaths: outputPaths,
+		Stats:       stats,
+	}
+}
+
+// fetchCaptions downloads the video's human-made captions in the job's
+// language into dir and returns them and their language code, or nil if
+// there are none. Failing to get captions isn't an error since whisper can
+// still run.
+func (p *Pipeline) fetchCaptions(meta *downloader.Metadata, dir string) ([]transcriber.Segment, string) {
+	want := p.config.Language
+	if p.config.Translate() {
+		// Only English captions match a translation.
+		want = "en"
+	}
+	lang := downloader.CaptionLanguage(meta, want)
+	if lang == "" {
+		p.events <- ProgressEvent{Step: "download", Progress: 0, Message: "No matching captions"}
+		return nil, ""
+	}
+
+	p.events <- ProgressEvent{Step: "download", Progress: 0, Message: "Fetching " + lang + " captions..."}
+	data, err := downloader.ReadCaptions(p.ctx, p.config.URL, lang, dir)
+	if err != nil {
+		p.events <- ProgressEvent{Step: "download", Progress: 0, Message: "Captions unavailable: " + err.Error()}
+		return nil, ""
+	}
+	segments, err := transcriber.ParseVTT(data, lang)
+	if err == nil && p.config.Clipped() {
+		segments = transcriber.ClipSegments(segments, p.config.Start, p.config.End)
+	}
+	if err != nil || len(segments) == 0 {
+		p.events <- ProgressEvent{Step: "download", Progress: 0, Message: "Captions unusable"}
+		return nil, ""
+	}
+	return segments, lang
+}
+
+// lookupCache returns the cache key for the job and, on a hit, the cached
+// segments. Cache errors are treated as misses.
+func (p *Pipeline) lookupCache(meta *downloader.Metadata, opts transcriber.Options) (*cache.Key, []transcriber.Segment) {
+	if p.cache == nil {
+		return nil, nil
 	}
+
+	var key cache.Key
+	if p.config.IsLocalFile() {
+		k, err := cache.FileKey(p.config.LocalFile, p.config.Model)
+		if err != nil {
+			return nil, nil
+		}
+		key = k
+	} else {
+		if meta.VideoID == "" {
+			return nil, nil
+		}
+		key = cache.VideoKey(meta.VideoID, p.config.Model)
+	}
+	key.Options = cacheOptions(opts, p.config)
+
+	if p.config.Force {
+		return &key, nil
+	}
+	entry, err := p.cache.Get(key)
+	if err != nil || entry == nil || len(entry.Segments) == 0 {
+		return &key, nil
+	}
+	return &key, entry.Segments
+}
+
+// cacheOptions describes the settings besides the model that change
+// whisper's output, so that they are part of the cache key.
+func cacheOptions(opts transcriber.Options, cfg *config.TranscriptionConfig) string {
+	task := config.TaskTranscribe
+	if opts.Translate {
+		task = config.TaskTranslate
+	}
+	key := fmt.Sprintf("language=%s task=%s", opts.Language, task)
+	if opts.Diarize != "" {
+		key += " diarize=" + opts.Diarize
+	}
+	if filters := cfg.AudioOptions().String(); filters != "" {
+		key += " audio=" + filters
+	}
+	if cfg.Clipped() {
+		key += fmt.Sprintf(" clip=%s-%s", config.FormatClipTime(cfg.Start), config.FormatClipTime(cfg.End))
+	}
+	if cfg.Chunked() {
+		// Chunks are decoded without the context of the previous one.
+		key += " chunk=" + cfg.ChunkLength.String()
+	}
+	if args := opts.Whisper.DecodingArgs(); len(args) > 0 {
+		key += " " + strings.Join(args, " ")
+	}
+	return key
+}
+
+// preprocessMessage summarizes what audio preparation did.
+func preprocessMessage(res *audio.Result) string {
+	switch {
+	case !res.Converted:
+		return "Already 16 kHz WAV"
+	case res.Offset > 0:
+		return fmt.Sprintf("Done (skipped %s of silence)", res.Offset.Round(100*time.Millisecond))
+	default:
+		return "Done"
+	}
+}
+
+// storeCache saves freshly transcribed segments under key.
+func (p *Pipeline) storeCache(key *cache.Key, meta *downloader.Metadata, segments []transcriber.Segment) error {
+	if p.cache == nil || key == nil {
+		return nil
+	}
+	return p.cache.Put(cache.Entry{
+		Key:      *key,
+		Metadata: *meta,
+		Segments: segments,
+	})
 }
 
-// Cancel stops the pipeline.
+// Cancel stops the pipeline and kills the tools it runs. Run then sends a
+// CancelledEvent instead of completing, unless it already completed.
 func (p *Pipeline) Cancel() {
 	p.cancel()
 }
 
+// fail reports err as the failure of step. Once the pipeline is cancelled,
+// errors are a consequence of that and it reports the cancellation instead.
+func (p *Pipeline) fail(step string, err error) {
+	if p.ctx.Err() != nil {
+		p.events <- CancelledEvent{Step: step}
+		return
+	}
+	p.events <- ErrorEvent{Step: step, Err: err}
+}
+
+// removeOutputs deletes output files of a job that didn't complete.
+func removeOutputs(paths []string) {
+	for _, path := range paths {
+		os.Remove(path)
+	}
+}
+
 // createLocalMetadata generates metadata from a local file path.
 func createLocalMetadata(filePath string) *downloader.Metadata {
 	filename := filepath.Base(filePath)
@@ -0,0 +1,25 @@
+package pipeline
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cyber/whisper-transcribe/internal/config"
+)
+
+func TestFailAfterCancel(t *testing.T) {
+	events := make(chan Event, 2)
+	p := New(&config.TranscriptionConfig{}, events)
+
+	p.fail("download", errors.New("yt-dlp failed"))
+	if e, ok := (<-events).(ErrorEvent); !ok || e.Step != "download" {
+		t.Errorf("before Cancel: got %#v, want ErrorEvent for download", e)
+	}
+
+	// Killed tools fail; that must not be reported as an error.
+	p.Cancel()
+	p.fail("transcribe", errors.New("signal: killed"))
+	if e, ok := (<-events).(CancelledEvent); !ok || e.Step != "transcribe" {
+		t.Errorf("after Cancel: got %#v, want CancelledEvent for transcribe", e)
+	}
+}
@@ -0,0 +1,9 @@
+// Package procgroup runs external tools so that cancelling them also stops
+// the processes they start, like the ffmpeg a yt-dlp download runs.
+package procgroup
+
+import "time"
+
+// waitDelay bounds how long Wait waits for the output pipes to close after
+// the command was cancelled.
+const waitDelay = 5 * time.Second
@@ -0,0 +1,16 @@
+//go:build !unix
+
+package procgroup
+
+import (
+	"context"
+	"os/exec"
+)
+
+// Command is exec.CommandContext. Without process groups only the command
+// itself is killed when ctx is done.
+func Command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.WaitDelay = waitDelay
+	return cmd
+}
@@ -0,0 +1,63 @@
+//go:build unix
+
+package procgroup
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestCommandKillsGroup(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	// The shell stands in for yt-dlp, and sleep for the ffmpeg it starts.
+	cmd := Command(ctx, "sh", "-c", "sleep 60 & echo $!; wait")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Skipf("no shell: %v", err)
+	}
+	line, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	child, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cancel()
+	if err := cmd.Wait(); err == nil {
+		t.Error("Wait succeeded after cancel")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for running(child) {
+		if time.Now().After(deadline) {
+			syscall.Kill(child, syscall.SIGKILL)
+			t.Fatalf("child %d survived cancellation", child)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// running reports whether the process exists and isn't a zombie waiting to
+// be reaped by whatever adopted it.
+func running(pid int) bool {
+	if syscall.Kill(pid, 0) != nil {
+		return false
+	}
+	stat, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return true
+	}
+	fields := strings.Fields(string(stat[strings.LastIndexByte(string(stat), ')')+1:]))
+	return len(fields) == 0 || fields[0] != "Z"
+}
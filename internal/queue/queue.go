@@ -0,0 +1,151 @@
+// Package queue runs many transcription jobs concurrently. Downloads run in
+// parallel since they are network-bound, while whisper runs are capped at a
+// fixed number of workers since they are CPU-bound.
+package queue
+
+import (
+	"sync"
+
+	"github.com/cyber/whisper-transcribe/internal/cache"
+	"github.com/cyber/whisper-transcribe/internal/config"
+	"github.com/cyber/whisper-transcribe/internal/history"
+	"github.com/cyber/whisper-transcribe/internal/pipeline"
+)
+
+// JobID identifies a job within a queue. IDs start at 1 and follow
+// submission order.
+type JobID int
+
+// Event is a pipeline event tagged with the job that produced it.
+type Event struct {
+	JobID JobID
+	Event pipeline.Event
+}
+
+// Options configures a queue.
+type Options struct {
+	// Workers caps concurrent whisper runs. Defaults to 1.
+	Workers int
+	// Downloads caps concurrent downloads. Zero means no limit.
+	Downloads int
+	// History records completed jobs when set.
+	History *history.Store
+	// Cache reuses earlier transcriptions when set.
+	Cache *cache.Cache
+}
+
+// Queue runs submitted jobs and multiplexes their events.
+type Queue struct {
+	events     chan Event
+	download   *pipeline.Limiter
+	transcribe *pipeline.Limiter
+	history    *history.Store
+	cache      *cache.Cache
+
+	mu     sync.Mutex
+	wg     sync.WaitGroup
+	nextID JobID
+	jobs   map[JobID]*pipeline.Pipeline
+	closed bool
+}
+
+// New creates a queue.
+func New(opts Options) *Queue {
+	q := &Queue{
+		events:     make(chan Event, 100),
+		transcribe: pipeline.NewLimiter(opts.Workers),
+		jobs:       make(map[JobID]*pipeline.Pipeline),
+		history:    opts.History,
+		cache:      opts.Cache,
+	}
+	if opts.Downloads > 0 {
+		q.download = pipeline.NewLimiter(opts.Downloads)
+	}
+	return q
+}
+
+// Events returns the multiplexed event stream of all jobs. It is closed
+// after Close has been called and every job has finished.
+func (q *Queue) Events() <-chan Event {
+	return q.events
+}
+
+// Submit starts a job and returns its ID. It panics if the queue has been
+// closed.
+func (q *Queue) Submit(cfg *config.TranscriptionConfig) JobID {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		panic("queue: Submit after Close")
+	}
+	q.nextID++
+	id := q.nextID
+
+	jobEvents := make(chan pipeline.Event, 100)
+	p := pipeline.New(cfg, jobEvents)
+	p.SetLimiters(q.download, q.transcribe)
+	p.SetHistory(q.history)
+	p.SetCache(q.cache)
+	q.jobs[id] = p
+	q.wg.Add(1)
+	q.mu.Unlock()
+
+	go func() {
+		p.Run()
+		close(jobEvents)
+	}()
+
+	go func() {
+		defer q.wg.Done()
+		for e := range jobEvents {
+			q.events <- Event{JobID: id, Event: e}
+		}
+		q.mu.Lock()
+		delete(q.jobs, id)
+		q.mu.Unlock()
+	}()
+
+	return id
+}
+
+// Close marks the queue as complete. The event stream is closed once all
+// submitted jobs have finished.
+func (q *Queue) Close() {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return
+	}
+	q.closed = true
+	q.mu.Unlock()
+
+	go func() {
+		q.wg.Wait()
+		close(q.events)
+	}()
+}
+
+// Cancel stops a running job. Unknown or finished jobs are ignored.
+func (q *Queue) Cancel(id JobID) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if p, ok := q.jobs[id]; ok {
+		p.Cancel()
+	}
+}
+
+// CancelAll stops every running job.
+func (q *Queue) CancelAll() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, p := range q.jobs {
+		p.Cancel()
+	}
+}
+
+// Wait blocks until every submitted job has finished, which for cancelled
+// jobs includes removing their temporary files. Events must still be
+// received meanwhile.
+func (q *Queue) Wait() {
+	q.wg.Wait()
+}
@@ -0,0 +1,54 @@
+package queue
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cyber/whisper-transcribe/internal/config"
+	"github.com/cyber/whisper-transcribe/internal/pipeline"
+)
+
+func TestQueueMultiplexesJobs(t *testing.T) {
+	q := New(Options{Workers: 2, Downloads: 2})
+
+	// Local files that don't exist fail in the preprocess step, before
+	// whisper runs, which is enough to exercise the queue without whisper
+	// installed.
+	dir := t.TempDir()
+	var ids []JobID
+	for _, name := range []string{"a.wav", "b.wav", "c.wav"} {
+		ids = append(ids, q.Submit(&config.TranscriptionConfig{
+			LocalFile: filepath.Join(dir, name),
+			Model:     "tiny",
+			OutputDir: dir,
+		}))
+	}
+	q.Close()
+
+	for i, id := range ids {
+		if id != JobID(i+1) {
+			t.Errorf("job %d has ID %d", i, id)
+		}
+	}
+
+	metadata := make(map[JobID]string)
+	finished := make(map[JobID]int)
+	for e := range q.Events() {
+		switch ev := e.Event.(type) {
+		case pipeline.MetadataEvent:
+			metadata[e.JobID] = ev.Title
+		case pipeline.ErrorEvent, pipeline.CompletedEvent:
+			finished[e.JobID]++
+		}
+	}
+
+	want := map[JobID]string{1: "a", 2: "b", 3: "c"}
+	for id, title := range want {
+		if metadata[id] != title {
+			t.Errorf("job %d title = %q, want %q", id, metadata[id], title)
+		}
+		if finished[id] != 1 {
+			t.Errorf("job %d finished %d times, want 1", id, finished[id])
+		}
+	}
+}
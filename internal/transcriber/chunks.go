@@ -0,0 +1,155 @@
+package transcriber
+
+import (
+	"context"
+	"runtime"
+	"sync"
+
+	"github.com/cyber/whisper-transcribe/internal/audio"
+)
+
+// Workers bounds the whisper processes of all jobs. A nil Workers places no
+// bound.
+type Workers interface {
+	// TryAcquire takes a slot if one is free without waiting.
+	TryAcquire() bool
+	// Release frees a slot taken by TryAcquire.
+	Release()
+}
+
+// TranscribeChunks transcribes the chunks of a recording with up to
+// parallel whisper processes at a time and joins their segments in order,
+// with timestamps relative to the recording. The caller holds one slot of
+// workers, which the chunks share; more chunks run at once only on slots
+// that are free meanwhile. Unless threads are set, the CPU cores are shared
+// between the processes. The first failure stops the other processes.
+func TranscribeChunks(ctx context.Context, chunks []audio.Chunk, opts Options, parallel int, workers Workers, onChunk ChunkFunc) ([]Segment, error) {
+	if len(chunks) == 1 {
+		opts.Offset += chunks[0].Offset
+		return Transcribe(ctx, chunks[0].Path, opts, onChunk)
+	}
+
+	parallel = min(max(parallel, 1), len(chunks))
+	if opts.Whisper.Threads == 0 {
+		opts.Whisper.Threads = max(runtime.NumCPU()/parallel, 1)
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Overall progress weighs each chunk's progress by its length.
+	tracker := &progressTracker{onChunk: onChunk}
+	var total float64
+	for _, c := range chunks {
+		total += c.Duration.Seconds()
+	}
+	weight := func(c audio.Chunk) float64 {
+		if total == 0 {
+			return 1 / float64(len(chunks))
+		}
+		return c.Duration.Seconds() / total
+	}
+	var mu sync.Mutex
+	progress := make([]float64, len(chunks))
+	overall := func(i int, p float64) float64 {
+		mu.Lock()
+		defer mu.Unlock()
+		progress[i] = p
+		sum := 0.0
+		for j, c := range chunks {
+			sum += progress[j] * weight(c)
+		}
+		return sum
+	}
+
+	slots := newJobSlots(workers)
+	results := make([][]Segment, len(chunks))
+	var firstErr error
+	var errOnce sync.Once
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i, c := range chunks {
+		wg.Add(1)
+		go func(i int, c audio.Chunk) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-runCtx.Done():
+				return
+			}
+			defer func() { <-sem }()
+			release := slots.acquire(runCtx)
+			if release == nil {
+				return
+			}
+			defer release()
+
+			chunkOpts := opts
+			chunkOpts.Offset += c.Offset
+			segments, err := Transcribe(runCtx, c.Path, chunkOpts, func(chunk Chunk) {
+				tracker.report(chunk, overall(i, chunk.Progress))
+			})
+			if err != nil {
+				errOnce.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			results[i] = segments
+		}(i, c)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	var segments []Segment
+	for _, r := range results {
+		segments = append(segments, r...)
+	}
+	return segments, nil
+}
+
+// jobSlots hands out whisper slots to the chunks of a job: the one slot the
+// job holds, and those of other workers while they are free.
+type jobSlots struct {
+	own     chan struct{}
+	workers Workers
+}
+
+func newJobSlots(workers Workers) *jobSlots {
+	s := &jobSlots{own: make(chan struct{}, 1), workers: workers}
+	s.own <- struct{}{}
+	return s
+}
+
+// acquire returns a function releasing the slot it took, or nil if ctx is
+// done before the job's own slot is free again.
+func (s *jobSlots) acquire(ctx context.Context) func() {
+	select {
+	case <-s.own:
+		return s.releaseOwn
+	default:
+	}
+	if s.workers == nil {
+		return func() {}
+	}
+	if s.workers.TryAcquire() {
+		return s.workers.Release
+	}
+	select {
+	case <-s.own:
+		return s.releaseOwn
+	case <-ctx.Done():
+		return nil
+	}
+}
+
+func (s *jobSlots) releaseOwn() {
+	s.own <- struct{}{}
+}
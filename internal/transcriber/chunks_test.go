@@ -0,0 +1,50 @@
+package transcriber
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// freeWorkers is a Workers with a fixed number of free slots.
+type freeWorkers struct {
+	free int
+}
+
+func (w *freeWorkers) TryAcquire() bool {
+	if w.free == 0 {
+		return false
+	}
+	w.free--
+	return true
+}
+
+func (w *freeWorkers) Release() { w.free++ }
+
+func TestJobSlots(t *testing.T) {
+	workers := &freeWorkers{free: 1}
+	slots := newJobSlots(workers)
+
+	// The job's own slot, then the one free worker.
+	own := slots.acquire(context.Background())
+	spare := slots.acquire(context.Background())
+	if own == nil || spare == nil || workers.free != 0 {
+		t.Fatalf("expected two slots, %d workers left free", workers.free)
+	}
+
+	// Further chunks wait for the job's slot rather than oversubscribing.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if slots.acquire(ctx) != nil {
+		t.Fatal("acquired a third slot")
+	}
+
+	own()
+	if slots.acquire(context.Background()) == nil {
+		t.Error("released slot not reusable")
+	}
+	spare()
+	if workers.free != 1 {
+		t.Errorf("spare slot not returned, %d free", workers.free)
+	}
+}
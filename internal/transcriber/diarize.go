@@ -0,0 +1,148 @@
+package transcriber
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Diarization modes.
+const (
+	// DiarizeStereo tells speakers apart by which channel of a stereo
+	// recording is louder, e.g. one speaker per channel in a call.
+	DiarizeStereo = "stereo"
+	// DiarizeTinydiarize uses the speaker-turn tokens of a tdrz model.
+	DiarizeTinydiarize = "tdrz"
+)
+
+// SpeakerLabel returns the label of the n-th speaker, e.g. SPEAKER_00.
+func SpeakerLabel(n int) string {
+	return fmt.Sprintf("SPEAKER_%02d", n)
+}
+
+// stereoSpeaker converts whisper's stereo speaker ("0", "1" or "?" when
+// undecided) to a label.
+func stereoSpeaker(s string) string {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return ""
+	}
+	return SpeakerLabel(n)
+}
+
+// SpeakerTurn is a span of audio attributed to one speaker.
+type SpeakerTurn struct {
+	Start   time.Duration
+	End     time.Duration
+	Speaker string
+}
+
+// LoadRTTM reads speaker turns from an RTTM file as written by external
+// diarization tools such as pyannote. Only SPEAKER lines are used.
+func LoadRTTM(path string) ([]SpeakerTurn, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open rttm: %w", err)
+	}
+	defer f.Close()
+
+	var turns []SpeakerTurn
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] != "SPEAKER" {
+			continue
+		}
+		// SPEAKER <file> <channel> <start> <duration> <NA> <NA> <speaker> ...
+		if len(fields) < 8 {
+			return nil, fmt.Errorf("rttm %s:%d: expected at least 8 fields", path, line)
+		}
+		start, err1 := strconv.ParseFloat(fields[3], 64)
+		dur, err2 := strconv.ParseFloat(fields[4], 64)
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("rttm %s:%d: invalid start or duration", path, line)
+		}
+		turns = append(turns, SpeakerTurn{
+			Start:   seconds(start),
+			End:     seconds(start + dur),
+			Speaker: fields[7],
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read rttm: %w", err)
+	}
+
+	sort.Slice(turns, func(i, j int) bool { return turns[i].Start < turns[j].Start })
+	return turns, nil
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second)).Round(time.Millisecond)
+}
+
+// AssignSpeakers returns a copy of segments with each segment attributed to
+// the speaker whose turns overlap it the most. Segments no turn overlaps
+// keep their speaker.
+func AssignSpeakers(segments []Segment, turns []SpeakerTurn) []Segment {
+	out := make([]Segment, len(segments))
+	for i, seg := range segments {
+		start, end := seg.StartTime(), seg.EndTime()
+		overlap := make(map[string]time.Duration)
+		best := ""
+		for _, t := range turns {
+			if t.End <= start {
+				continue
+			}
+			if t.Start >= end {
+				break
+			}
+			overlap[t.Speaker] += min(end, t.End) - max(start, t.Start)
+			if best == "" || overlap[t.Speaker] > overlap[best] {
+				best = t.Speaker
+			}
+		}
+		if best != "" {
+			seg.Speaker = best
+		}
+		out[i] = seg
+	}
+	return out
+}
+
+// RenameSpeakers returns a copy of segments with speaker labels replaced by
+// names. Labels are matched case-insensitively, since config keys are
+// lowercased when they are read.
+func RenameSpeakers(segments []Segment, names map[string]string) []Segment {
+	if len(names) == 0 {
+		return segments
+	}
+	upper := make(map[string]string, len(names))
+	for label, name := range names {
+		upper[strings.ToUpper(label)] = name
+	}
+
+	out := make([]Segment, len(segments))
+	for i, seg := range segments {
+		if name, ok := upper[strings.ToUpper(seg.Speaker)]; ok {
+			seg.Speaker = name
+		}
+		out[i] = seg
+	}
+	return out
+}
+
+// HasSpeakers reports whether any segment is attributed to a speaker.
+func HasSpeakers(segments []Segment) bool {
+	for _, seg := range segments {
+		if seg.Speaker != "" {
+			return true
+		}
+	}
+	return false
+}
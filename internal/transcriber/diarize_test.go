@@ -0,0 +1,71 @@
+package transcriber
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseJSONSpeakers(t *testing.T) {
+	stereo := `{"result": {"language": "en"}, "transcription": [
+		{"offsets": {"from": 0, "to": 1000}, "text": " Hello.", "speaker": "0"},
+		{"offsets": {"from": 1000, "to": 2000}, "text": " Hi.", "speaker": "1"},
+		{"offsets": {"from": 2000, "to": 3000}, "text": " Hm.", "speaker": "?"}
+	]}`
+	segments, err := parseJSON([]byte(stereo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []string{"SPEAKER_00", "SPEAKER_01", ""} {
+		if segments[i].Speaker != want {
+			t.Errorf("stereo segment %d speaker = %q, want %q", i, segments[i].Speaker, want)
+		}
+	}
+
+	tdrz := `{"result": {"language": "en"}, "transcription": [
+		{"offsets": {"from": 0, "to": 1000}, "text": " How are you?", "speaker_turn_next": true},
+		{"offsets": {"from": 1000, "to": 2000}, "text": " Fine.", "speaker_turn_next": false},
+		{"offsets": {"from": 2000, "to": 3000}, "text": " Thanks.", "speaker_turn_next": true},
+		{"offsets": {"from": 3000, "to": 4000}, "text": " Good.", "speaker_turn_next": false}
+	]}`
+	segments, err = parseJSON([]byte(tdrz))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []string{"SPEAKER_00", "SPEAKER_01", "SPEAKER_01", "SPEAKER_00"} {
+		if segments[i].Speaker != want {
+			t.Errorf("tdrz segment %d speaker = %q, want %q", i, segments[i].Speaker, want)
+		}
+	}
+}
+
+func TestRTTMSpeakers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "talk.rttm")
+	err := os.WriteFile(path, []byte(
+		"SPEAKER talk 1 0.00 2.50 <NA> <NA> SPEAKER_00 <NA> <NA>\n"+
+			"SPEAKER talk 1 2.50 4.00 <NA> <NA> SPEAKER_01 <NA> <NA>\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	turns, err := LoadRTTM(path)
+	if err != nil {
+		t.Fatalf("LoadRTTM: %v", err)
+	}
+	if len(turns) != 2 || turns[1].Start != 2500*time.Millisecond || turns[1].End != 6500*time.Millisecond {
+		t.Fatalf("turns = %+v", turns)
+	}
+
+	segments := []Segment{
+		{Start: "00:00:00.000", End: "00:00:02.000", Text: "Welcome."},
+		// Mostly in the second turn.
+		{Start: "00:00:02.000", End: "00:00:05.000", Text: "Thank you."},
+		{Start: "00:00:10.000", End: "00:00:11.000", Text: "Bye."},
+	}
+	got := RenameSpeakers(AssignSpeakers(segments, turns), map[string]string{"speaker_00": "Alice"})
+	for i, want := range []string{"Alice", "SPEAKER_01", ""} {
+		if got[i].Speaker != want {
+			t.Errorf("segment %d speaker = %q, want %q", i, got[i].Speaker, want)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package transcriber
+
+import "strings"
+
+// LanguageAuto lets whisper detect the spoken language.
+const LanguageAuto = "auto"
+
+// Languages are the language codes whisper.cpp accepts for -l, most
+// commonly used first.
+var Languages = []string{
+	"en", "de", "es", "fr", "it", "pt", "nl", "pl", "ru", "uk", "tr", "ar",
+	"zh", "ja", "ko", "hi", "sv", "da", "no", "fi", "cs", "el", "hu", "ro",
+	"he", "id", "ms", "th", "vi", "ca", "bg", "hr", "sk", "sl", "sr", "lt",
+	"lv", "et", "fa", "ur", "ta", "te", "ml", "kn", "mr", "bn", "gu", "pa",
+	"ne", "si", "km", "lo", "my", "tl", "sw", "yo", "af", "sq", "am", "hy",
+	"as", "az", "ba", "eu", "be", "bs", "br", "cy", "fo", "gl", "ka", "ht",
+	"ha", "haw", "is", "jw", "kk", "la", "lb", "ln", "mk", "mg", "mi", "mn",
+	"mt", "nn", "oc", "ps", "sa", "sd", "sn", "so", "su", "tg", "tk", "tt",
+	"uz", "yi", "yue",
+}
+
+// IsLanguage reports whether code is auto or a language whisper knows.
+func IsLanguage(code string) bool {
+	if code == LanguageAuto {
+		return true
+	}
+	for _, l := range Languages {
+		if l == code {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEnglishOnly reports whether model is an English-only (.en) model,
+// which can neither translate nor transcribe other languages.
+func IsEnglishOnly(model string) bool {
+	return strings.HasSuffix(model, ".en") || strings.Contains(model, ".en-")
+}
+
+// EnglishOnlyFits reports whether an English-only model can run a job with
+// the given language and task: one that transcribes English, or detects
+// the language, which such a model always takes to be English.
+func EnglishOnlyFits(language string, translate bool) bool {
+	return !translate && (language == "" || language == "en" || language == LanguageAuto)
+}
+
+// DetectedLanguage returns the language whisper reported for the segments,
+// or "" if none did.
+func DetectedLanguage(segments []Segment) string {
+	for _, seg := range segments {
+		if seg.Language != "" {
+			return seg.Language
+		}
+	}
+	return ""
+}
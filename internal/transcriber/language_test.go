@@ -0,0 +1,59 @@
+package transcriber
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsEnglishOnly(t *testing.T) {
+	for model, want := range map[string]bool{
+		"base.en":       true,
+		"small.en-q5_1": true,
+		"base":          false,
+		"large-v3":      false,
+	} {
+		if got := IsEnglishOnly(model); got != want {
+			t.Errorf("IsEnglishOnly(%q) = %v, want %v", model, got, want)
+		}
+	}
+}
+
+func TestDetectedLanguage(t *testing.T) {
+	segments := []Segment{{Text: "..."}, {Text: "Hola", Language: "es"}}
+	if got := DetectedLanguage(segments); got != "es" {
+		t.Errorf("DetectedLanguage = %q, want es", got)
+	}
+	if !IsLanguage("auto") || !IsLanguage("de") || IsLanguage("german") {
+		t.Error("IsLanguage accepts the wrong codes")
+	}
+}
+
+func TestEnglishOnlyFallback(t *testing.T) {
+	modelDir := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("WHISPER_MODEL_PATH", modelDir)
+	os.WriteFile(filepath.Join(modelDir, "ggml-base.en.bin"), []byte("base.en"), 0644)
+
+	if !EnglishOnlyFits(LanguageAuto, false) || !EnglishOnlyFits("en", false) {
+		t.Error("English-only models should run English and auto-detected jobs")
+	}
+	if EnglishOnlyFits("de", false) || EnglishOnlyFits("en", true) {
+		t.Error("English-only models can't run German or translation jobs")
+	}
+
+	if !EnglishOnlyFallback("base") {
+		t.Error("base should fall back to the installed base.en")
+	}
+	if err := CheckModel("base", EnglishOnlyFits(LanguageAuto, false)); err != nil {
+		t.Errorf("auto-detected job with only base.en installed: %v", err)
+	}
+	if err := CheckModel("base", EnglishOnlyFits("de", false)); err == nil {
+		t.Error("German job should not accept base.en")
+	}
+
+	os.WriteFile(filepath.Join(modelDir, "ggml-base.bin"), []byte("base"), 0644)
+	if EnglishOnlyFallback("base") {
+		t.Error("no fallback when the multilingual model is installed")
+	}
+}
@@ -0,0 +1,167 @@
+package transcriber
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Options control a whisper run.
+type Options struct {
+	Model string
+	// Language is a whisper language code or LanguageAuto. Empty leaves
+	// the binary's default, which is English.
+	Language string
+	// Translate translates the speech to English instead of transcribing it.
+	Translate bool
+	// Diarize is DiarizeStereo, DiarizeTinydiarize or empty.
+	Diarize string
+	// Whisper holds the decoding parameters.
+	Whisper WhisperOptions
+	// Offset is added to every timestamp, for audio that starts partway
+	// into the original recording.
+	Offset time.Duration
+	// TempDir is where whisper's output is written before it is parsed.
+	// Empty uses the system temp directory.
+	TempDir string
+}
+
+// args returns the whisper.cpp flags for the options other than the model.
+func (o Options) args() []string {
+	var args []string
+	if o.Language != "" {
+		args = append(args, "-l", o.Language)
+	}
+	if o.Translate {
+		args = append(args, "--translate")
+	}
+	switch o.Diarize {
+	case DiarizeStereo:
+		args = append(args, "--diarize")
+	case DiarizeTinydiarize:
+		args = append(args, "--tinydiarize")
+	}
+	if o.Whisper.Threads > 0 {
+		args = append(args, "-t", strconv.Itoa(o.Whisper.Threads))
+	}
+	return append(args, o.Whisper.DecodingArgs()...)
+}
+
+// WhisperOptions are whisper.cpp decoding parameters. Zero values leave the
+// binary's defaults.
+type WhisperOptions struct {
+	Threads    int `mapstructure:"threads"`
+	Processors int `mapstructure:"processors"`
+	BeamSize   int `mapstructure:"beam_size"`
+	BestOf     int `mapstructure:"best_of"`
+	// Temperature is the initial sampling temperature; whisper raises it
+	// on fallback.
+	Temperature      float64 `mapstructure:"temperature"`
+	EntropyThreshold float64 `mapstructure:"entropy_threshold"`
+	LogprobThreshold float64 `mapstructure:"logprob_threshold"`
+	// MaxLen is the maximum segment length in characters.
+	MaxLen      int  `mapstructure:"max_len"`
+	SplitOnWord bool `mapstructure:"split_on_word"`
+	// Prompt is the initial prompt, used to prime spelling and style.
+	Prompt string `mapstructure:"prompt"`
+}
+
+// DecodingArgs returns the whisper.cpp flags for every option that can
+// change the transcript, which is all of them except Threads.
+func (o WhisperOptions) DecodingArgs() []string {
+	var args []string
+	if o.Processors > 0 {
+		args = append(args, "-p", strconv.Itoa(o.Processors))
+	}
+	if o.BeamSize > 0 {
+		args = append(args, "-bs", strconv.Itoa(o.BeamSize))
+	}
+	if o.BestOf > 0 {
+		args = append(args, "-bo", strconv.Itoa(o.BestOf))
+	}
+	if o.Temperature > 0 {
+		args = append(args, "-tp", formatFloat(o.Temperature))
+	}
+	if o.EntropyThreshold != 0 {
+		args = append(args, "-et", formatFloat(o.EntropyThreshold))
+	}
+	if o.LogprobThreshold != 0 {
+		args = append(args, "-lpt", formatFloat(o.LogprobThreshold))
+	}
+	if o.MaxLen > 0 {
+		args = append(args, "-ml", strconv.Itoa(o.MaxLen))
+	}
+	if o.SplitOnWord {
+		args = append(args, "-sow")
+	}
+	if o.Prompt != "" {
+		args = append(args, "--prompt", o.Prompt)
+	}
+	return args
+}
+
+// WhisperOverrides adjusts WhisperOptions, e.g. a configured profile that
+// builds on a built-in one. Only fields that are set (non-nil) apply, so
+// zero and false can override a value too.
+type WhisperOverrides struct {
+	Threads          *int     `mapstructure:"threads"`
+	Processors       *int     `mapstructure:"processors"`
+	BeamSize         *int     `mapstructure:"beam_size"`
+	BestOf           *int     `mapstructure:"best_of"`
+	Temperature      *float64 `mapstructure:"temperature"`
+	EntropyThreshold *float64 `mapstructure:"entropy_threshold"`
+	LogprobThreshold *float64 `mapstructure:"logprob_threshold"`
+	MaxLen           *int     `mapstructure:"max_len"`
+	SplitOnWord      *bool    `mapstructure:"split_on_word"`
+	Prompt           *string  `mapstructure:"prompt"`
+}
+
+// Merge returns o with every field set in over applied on top.
+func (o WhisperOptions) Merge(over WhisperOverrides) WhisperOptions {
+	if over.Threads != nil {
+		o.Threads = *over.Threads
+	}
+	if over.Processors != nil {
+		o.Processors = *over.Processors
+	}
+	if over.BeamSize != nil {
+		o.BeamSize = *over.BeamSize
+	}
+	if over.BestOf != nil {
+		o.BestOf = *over.BestOf
+	}
+	if over.Temperature != nil {
+		o.Temperature = *over.Temperature
+	}
+	if over.EntropyThreshold != nil {
+		o.EntropyThreshold = *over.EntropyThreshold
+	}
+	if over.LogprobThreshold != nil {
+		o.LogprobThreshold = *over.LogprobThreshold
+	}
+	if over.MaxLen != nil {
+		o.MaxLen = *over.MaxLen
+	}
+	if over.SplitOnWord != nil {
+		o.SplitOnWord = *over.SplitOnWord
+	}
+	if over.Prompt != nil {
+		o.Prompt = *over.Prompt
+	}
+	return o
+}
+
+// Validate checks the options are within the ranges whisper.cpp accepts.
+func (o WhisperOptions) Validate() error {
+	switch {
+	case o.Threads < 0, o.Processors < 0, o.BeamSize < 0, o.BestOf < 0, o.MaxLen < 0:
+		return fmt.Errorf("threads, processors, beam_size, best_of and max_len must not be negative")
+	case o.Temperature < 0 || o.Temperature > 1:
+		return fmt.Errorf("temperature %g is outside 0-1", o.Temperature)
+	}
+	return nil
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,57 @@
+package transcriber
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOptionsArgs(t *testing.T) {
+	tests := []struct {
+		opts Options
+		want []string
+	}{
+		{Options{Model: "base"}, nil},
+		{Options{Language: "de"}, []string{"-l", "de"}},
+		{Options{Language: LanguageAuto, Translate: true}, []string{"-l", "auto", "--translate"}},
+		{
+			Options{Whisper: WhisperOptions{
+				Threads: 8, Processors: 2, BeamSize: 5, BestOf: 5, Temperature: 0.2,
+				EntropyThreshold: 2.8, LogprobThreshold: -0.8, MaxLen: 80,
+				SplitOnWord: true, Prompt: "kubectl, etcd",
+			}},
+			[]string{
+				"-t", "8", "-p", "2", "-bs", "5", "-bo", "5", "-tp", "0.2",
+				"-et", "2.8", "-lpt", "-0.8", "-ml", "80", "-sow", "--prompt", "kubectl, etcd",
+			},
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.opts.args(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%+v.args() = %v, want %v", tt.opts, got, tt.want)
+		}
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestWhisperOptionsMerge(t *testing.T) {
+	base := WhisperOptions{MaxLen: 80, BeamSize: 5, SplitOnWord: true}
+	got := base.Merge(WhisperOverrides{Threads: ptr(16), BeamSize: ptr(8)})
+	want := WhisperOptions{Threads: 16, MaxLen: 80, BeamSize: 8, SplitOnWord: true}
+	if got != want {
+		t.Errorf("Merge = %+v, want %+v", got, want)
+	}
+
+	// Zero and false are settings too.
+	got = base.Merge(WhisperOverrides{MaxLen: ptr(0), SplitOnWord: ptr(false)})
+	want = WhisperOptions{BeamSize: 5}
+	if got != want {
+		t.Errorf("Merge = %+v, want %+v", got, want)
+	}
+
+	if err := (WhisperOptions{Temperature: 1.5}).Validate(); err == nil {
+		t.Error("expected error for temperature above 1")
+	}
+}
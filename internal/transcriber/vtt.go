@@ -0,0 +1,70 @@
+package transcriber
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	vttTimingRe = regexp.MustCompile(`^((?:\d+:)?\d{2}:\d{2}\.\d{3})\s+-->\s+((?:\d+:)?\d{2}:\d{2}\.\d{3})`)
+	vttTagRe    = regexp.MustCompile(`<[^>]*>`)
+)
+
+// ParseVTT converts WebVTT subtitles into segments, one per cue, tagged
+// with lang. Styling and timing tags inside cues are dropped.
+func ParseVTT(data []byte, lang string) ([]Segment, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	if !strings.HasPrefix(strings.TrimPrefix(text, "\ufeff"), "WEBVTT") {
+		return nil, fmt.Errorf("parse captions: not a WebVTT file")
+	}
+
+	var segments []Segment
+	for _, block := range strings.Split(text, "\n\n") {
+		lines := strings.Split(strings.TrimSpace(block), "\n")
+		// The timing line follows an optional cue identifier.
+		i := 0
+		for i < len(lines) && !vttTimingRe.MatchString(lines[i]) {
+			i++
+		}
+		if i == len(lines) {
+			// Header, NOTE, STYLE or REGION block.
+			continue
+		}
+
+		m := vttTimingRe.FindStringSubmatch(lines[i])
+		start, err1 := parseVTTTime(m[1])
+		end, err2 := parseVTTTime(m[2])
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("parse captions: invalid timing %q", lines[i])
+		}
+
+		var words []string
+		for _, line := range lines[i+1:] {
+			line = html.UnescapeString(vttTagRe.ReplaceAllString(line, ""))
+			words = append(words, strings.Fields(line)...)
+		}
+		if len(words) == 0 {
+			continue
+		}
+
+		segments = append(segments, Segment{
+			Start:     FormatTimestamp(start),
+			End:       FormatTimestamp(end),
+			Text:      strings.Join(words, " "),
+			Timestamp: formatTimestamp(FormatTimestamp(start)),
+			Language:  lang,
+		})
+	}
+	return segments, nil
+}
+
+// parseVTTTime parses HH:MM:SS.mmm, or MM:SS.mmm as WebVTT allows.
+func parseVTTTime(s string) (time.Duration, error) {
+	if strings.Count(s, ":") == 1 {
+		s = "00:" + s
+	}
+	return ParseTimestamp(s)
+}
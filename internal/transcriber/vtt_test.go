@@ -0,0 +1,31 @@
+package transcriber
+
+import "testing"
+
+const captionsVTT = "WEBVTT\r\nKind: captions\r\nLanguage: de\r\n\r\n" +
+	"NOTE created by hand\r\n\r\n" +
+	"1\r\n00:00:01.000 --> 00:00:03.500 align:start position:0%\r\nGuten <c.yellow>Tag</c>\r\nzusammen.\r\n\r\n" +
+	"01:02.000 --> 01:04.000\r\nFish &amp; Chips\r\n\r\n" +
+	"00:01:05.000 --> 00:01:06.000\r\n<i> </i>\r\n"
+
+func TestParseVTT(t *testing.T) {
+	segments, err := ParseVTT([]byte(captionsVTT), "de")
+	if err != nil {
+		t.Fatalf("ParseVTT: %v", err)
+	}
+	if len(segments) != 2 {
+		t.Fatalf("expected 2 segments, got %+v", segments)
+	}
+
+	first := segments[0]
+	if first.Text != "Guten Tag zusammen." || first.Start != "00:00:01.000" || first.End != "00:00:03.500" || first.Language != "de" {
+		t.Errorf("first = %+v", first)
+	}
+	if segments[1].Text != "Fish & Chips" || segments[1].Start != "00:01:02.000" {
+		t.Errorf("second = %+v", segments[1])
+	}
+
+	if _, err := ParseVTT([]byte("1\n00:00:01,000 --> 00:00:02,000\nSRT\n"), "en"); err == nil {
+		t.Error("expected error for non-WebVTT input")
+	}
+}
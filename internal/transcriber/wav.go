@@ -0,0 +1,16 @@
+package transcriber
+
+import (
+	"time"
+
+	"github.com/cyber/whisper-transcribe/internal/audio"
+)
+
+// AudioDuration returns the duration of a WAV file by reading its header.
+func AudioDuration(path string) (time.Duration, error) {
+	h, err := audio.ReadHeader(path)
+	if err != nil {
+		return 0, err
+	}
+	return h.Duration(), nil
+}
@@ -0,0 +1,83 @@
+package transcriber
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeTestWAV(t *testing.T, dataBytes int) string {
+	t.Helper()
+
+	var b bytes.Buffer
+	le := binary.LittleEndian
+	b.WriteString("RIFF")
+	binary.Write(&b, le, uint32(36+10+dataBytes))
+	b.WriteString("WAVE")
+
+	// 16 kHz mono 16-bit PCM
+	b.WriteString("fmt ")
+	binary.Write(&b, le, uint32(16))
+	binary.Write(&b, le, uint16(1))
+	binary.Write(&b, le, uint16(1))
+	binary.Write(&b, le, uint32(16000))
+	binary.Write(&b, le, uint32(32000))
+	binary.Write(&b, le, uint16(2))
+	binary.Write(&b, le, uint16(16))
+
+	// Odd-sized chunk to exercise padding
+	b.WriteString("LIST")
+	binary.Write(&b, le, uint32(1))
+	b.Write([]byte{0, 0})
+
+	b.WriteString("data")
+	binary.Write(&b, le, uint32(dataBytes))
+	b.Write(make([]byte, dataBytes))
+
+	path := filepath.Join(t.TempDir(), "test.wav")
+	if err := os.WriteFile(path, b.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestAudioDuration(t *testing.T) {
+	path := writeTestWAV(t, 48000)
+
+	d, err := AudioDuration(path)
+	if err != nil {
+		t.Fatalf("AudioDuration failed: %v", err)
+	}
+	if d != 1500*time.Millisecond {
+		t.Errorf("expected 1.5s, got %v", d)
+	}
+
+	if _, err := AudioDuration("testdata/whisper_basic.json"); err == nil {
+		t.Error("expected error for non-WAV file")
+	}
+}
+
+func TestProgressTracker(t *testing.T) {
+	var got []float64
+	tracker := &progressTracker{onChunk: func(c Chunk) {
+		got = append(got, c.Progress)
+	}}
+
+	tracker.report(Chunk{}, 0.1)
+	tracker.report(Chunk{}, 0.1)
+	tracker.report(Chunk{Text: "late segment"}, 0.05)
+	tracker.report(Chunk{}, 2.5)
+
+	want := []float64{0.1, 0.1, 1}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("update %d: expected %v, got %v", i, want[i], got[i])
+		}
+	}
+}
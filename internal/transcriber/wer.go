@@ -0,0 +1,89 @@
+package transcriber
+
+import (
+	"strings"
+	"unicode"
+)
+
+// WER is the word error rate of a transcript against a reference.
+type WER struct {
+	// ReferenceWords is the number of words in the reference.
+	ReferenceWords int
+	Substitutions  int
+	Deletions      int
+	Insertions     int
+}
+
+// Rate returns (S + D + I) / N, or 0 for an empty reference.
+func (w WER) Rate() float64 {
+	if w.ReferenceWords == 0 {
+		return 0
+	}
+	return float64(w.Substitutions+w.Deletions+w.Insertions) / float64(w.ReferenceWords)
+}
+
+// WordErrorRate compares hypothesis against reference word by word, after
+// lowercasing and removing punctuation so that only the words count.
+func WordErrorRate(reference, hypothesis []Segment) WER {
+	ref := normalizedWords(reference)
+	hyp := normalizedWords(hypothesis)
+
+	// Levenshtein distance over words, keeping only two rows so that long
+	// transcripts don't need a quadratic amount of memory. Each cell keeps
+	// the edit counts of its cheapest path.
+	type cell struct{ cost, sub, del, ins int }
+	prev := make([]cell, len(hyp)+1)
+	cur := make([]cell, len(hyp)+1)
+	for j := range prev {
+		prev[j] = cell{cost: j, ins: j}
+	}
+
+	for i := 1; i <= len(ref); i++ {
+		cur[0] = cell{cost: i, del: i}
+		for j := 1; j <= len(hyp); j++ {
+			if ref[i-1] == hyp[j-1] {
+				cur[j] = prev[j-1]
+				continue
+			}
+			sub, del, ins := prev[j-1], prev[j], cur[j-1]
+			switch {
+			case sub.cost <= del.cost && sub.cost <= ins.cost:
+				sub.cost++
+				sub.sub++
+				cur[j] = sub
+			case del.cost <= ins.cost:
+				del.cost++
+				del.del++
+				cur[j] = del
+			default:
+				ins.cost++
+				ins.ins++
+				cur[j] = ins
+			}
+		}
+		prev, cur = cur, prev
+	}
+
+	last := prev[len(hyp)]
+	return WER{
+		ReferenceWords: len(ref),
+		Substitutions:  last.sub,
+		Deletions:      last.del,
+		Insertions:     last.ins,
+	}
+}
+
+func normalizedWords(segments []Segment) []string {
+	var words []string
+	for _, seg := range segments {
+		for _, w := range strings.Fields(strings.ToLower(seg.Text)) {
+			w = strings.TrimFunc(w, func(r rune) bool {
+				return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+			})
+			if w != "" {
+				words = append(words, w)
+			}
+		}
+	}
+	return words
+}
@@ -0,0 +1,24 @@
+package transcriber
+
+import "testing"
+
+func TestWordErrorRate(t *testing.T) {
+	ref := []Segment{{Text: "The quick brown fox"}, {Text: "jumps over the lazy dog."}}
+	hyp := []Segment{{Text: "the quick, brown fax jumps over lazy dog and"}}
+
+	w := WordErrorRate(ref, hyp)
+	want := WER{ReferenceWords: 9, Substitutions: 1, Deletions: 1, Insertions: 1}
+	if w != want {
+		t.Errorf("WordErrorRate = %+v, want %+v", w, want)
+	}
+	if rate := w.Rate(); rate < 0.333 || rate > 0.334 {
+		t.Errorf("Rate = %f, want 1/3", rate)
+	}
+
+	if w := WordErrorRate(ref, ref); w.Rate() != 0 {
+		t.Errorf("identical transcripts have WER %f", w.Rate())
+	}
+	if w := WordErrorRate(nil, hyp); w.Rate() != 0 {
+		t.Errorf("empty reference has WER %f", w.Rate())
+	}
+}
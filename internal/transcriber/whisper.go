@@ -8,9 +8,13 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/cyber/whisper-transcribe/internal/models"
+	"github.com/cyber/whisper-transcribe/internal/procgroup"
 )
 
 // Segment represents a transcribed segment with timestamps.
@@ -19,9 +23,20 @@ type Segment struct {
 	End       string
 	Text      string
 	Timestamp string
+
+	// Language is the language detected by whisper, e.g. "en".
+	Language string
+	// Speaker labels who is talking, e.g. SPEAKER_00 or a configured
+	// name. Empty without diarization.
+	Speaker string
+	// Tokens holds per-token timing and confidence when available.
+	Tokens []Token
+	// Probability is the mean token probability of the segment.
+	Probability float64
 }
 
-// Chunk represents a streaming transcription chunk.
+// Chunk represents a streaming transcription chunk. Chunks with empty Text
+// carry progress updates only.
 type Chunk struct {
 	Text      string
 	Timestamp string
@@ -32,25 +47,32 @@ type Chunk struct {
 type ChunkFunc func(chunk Chunk)
 
 // Transcribe runs whisper.cpp on the audio file.
-func Transcribe(ctx context.Context, audioPath string, model string, onChunk ChunkFunc) ([]Segment, error) {
+func Transcribe(ctx context.Context, audioPath string, opts Options, onChunk ChunkFunc) ([]Segment, error) {
 	whisperBin := findWhisperBinary()
 	if whisperBin == "" {
 		return nil, fmt.Errorf("whisper binary not found in PATH (tried: whisper-cpp, whisper, main)")
 	}
 
-	modelPath := findModelPath(model)
+	modelPath := findModelPath(opts.Model, EnglishOnlyFits(opts.Language, opts.Translate))
 	if modelPath == "" {
-		return nil, fmt.Errorf("model '%s' not found - ensure whisper models are installed", model)
+		return nil, fmt.Errorf("model '%s' not found - ensure whisper models are installed", opts.Model)
+	}
+
+	outDir, err := os.MkdirTemp(opts.TempDir, "whisper-transcribe-out-")
+	if err != nil {
+		return nil, fmt.Errorf("create output dir: %w", err)
 	}
+	defer os.RemoveAll(outDir)
+	outPrefix := filepath.Join(outDir, "transcript")
 
-	cmd := exec.CommandContext(ctx, whisperBin,
+	args := []string{
 		"-m", modelPath,
 		"-f", audioPath,
-		"--output-txt",
+		"--output-json-full",
+		"-of", outPrefix,
 		"--print-progress",
-		"-pp",
-		"-ml", "80",
-	)
+	}
+	cmd := procgroup.Command(ctx, whisperBin, append(args, opts.args()...)...)
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
@@ -66,57 +88,109 @@ func Transcribe(ctx context.Context, audioPath string, model string, onChunk Chu
 		return nil, fmt.Errorf("start whisper: %w", err)
 	}
 
-	var segments []Segment
+	// Segment end times are turned into progress using the audio length.
+	// Non-WAV input has no cheap duration probe, so it relies on whisper's
+	// own progress reports alone.
+	tracker := &progressTracker{onChunk: onChunk}
+	if d, err := AudioDuration(audioPath); err == nil {
+		tracker.duration = d
+	}
+
 	progressRe := regexp.MustCompile(`progress\s*=\s*(\d+)`)
 	timestampRe := regexp.MustCompile(`\[(\d{2}:\d{2}:\d{2}[.,]\d{3})\s*-->\s*(\d{2}:\d{2}:\d{2}[.,]\d{3})\]\s*(.*)`)
 
+	// Keep the last stderr line so failures carry whisper's own message.
+	var lastErrLine string
+	stderrDone := make(chan struct{})
 	go func() {
+		defer close(stderrDone)
 		scanner := bufio.NewScanner(stderr)
 		for scanner.Scan() {
 			line := scanner.Text()
 			if matches := progressRe.FindStringSubmatch(line); len(matches) > 1 {
-				// Progress updates from stderr
+				if pct, err := strconv.Atoi(matches[1]); err == nil {
+					tracker.report(Chunk{}, float64(pct)/100.0)
+				}
+				continue
+			}
+			if strings.TrimSpace(line) != "" {
+				lastErrLine = strings.TrimSpace(line)
 			}
 		}
 	}()
 
+	// Stdout is only used for live streaming; the JSON file written by
+	// whisper is the source of truth for the final segments.
 	scanner := bufio.NewScanner(stdout)
-	lineCount := 0
-
 	for scanner.Scan() {
 		line := scanner.Text()
-		lineCount++
 
 		if matches := timestampRe.FindStringSubmatch(line); len(matches) == 4 {
-			seg := Segment{
-				Start:     normalizeTimestamp(matches[1]),
-				End:       normalizeTimestamp(matches[2]),
-				Text:      strings.TrimSpace(matches[3]),
-				Timestamp: formatTimestamp(matches[1]),
+			text := strings.TrimSpace(matches[3])
+			if text == "" {
+				continue
 			}
-
-			if seg.Text != "" {
-				segments = append(segments, seg)
-
-				if onChunk != nil {
-					onChunk(Chunk{
-						Text:      seg.Text,
-						Timestamp: seg.Timestamp,
-						Progress:  float64(lineCount) / 100.0,
-					})
-				}
+			progress := 0.0
+			if end, err := ParseTimestamp(matches[2]); err == nil && tracker.duration > 0 {
+				progress = end.Seconds() / tracker.duration.Seconds()
+			}
+			timestamp := matches[1]
+			if start, err := ParseTimestamp(timestamp); err == nil && opts.Offset > 0 {
+				timestamp = FormatTimestamp(start + opts.Offset)
 			}
+			tracker.report(Chunk{
+				Text:      text,
+				Timestamp: formatTimestamp(timestamp),
+			}, progress)
 		}
 	}
 
+	<-stderrDone
 	if err := cmd.Wait(); err != nil {
-		if len(segments) > 0 {
-			return segments, nil
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if lastErrLine != "" {
+			return nil, fmt.Errorf("whisper failed: %w: %s", err, lastErrLine)
 		}
 		return nil, fmt.Errorf("whisper failed: %w", err)
 	}
 
-	return segments, nil
+	segments, err := parseJSONOutput(outPrefix + ".json")
+	if err != nil {
+		return nil, err
+	}
+	return ShiftSegments(segments, opts.Offset), nil
+}
+
+// progressTracker merges whisper's progress reports with segment end times
+// into a single value that stays within 0-1 and never moves backwards. It
+// also serializes chunk callbacks from the stdout and stderr readers.
+type progressTracker struct {
+	mu       sync.Mutex
+	duration time.Duration
+	value    float64
+	onChunk  ChunkFunc
+}
+
+func (t *progressTracker) report(chunk Chunk, progress float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	progress = min(max(progress, 0), 1)
+	if progress <= t.value {
+		// Progress-only updates that do not advance are dropped.
+		if chunk.Text == "" {
+			return
+		}
+	} else {
+		t.value = progress
+	}
+
+	chunk.Progress = t.value
+	if t.onChunk != nil {
+		t.onChunk(chunk)
+	}
 }
 
 func findWhisperBinary() string {
@@ -136,27 +210,12 @@ func findWhisperBinary() string {
 	return ""
 }
 
-func findModelPath(model string) string {
-	basePaths := []string{
-		os.Getenv("WHISPER_MODEL_PATH"),
-		models.GetModelsDir(),
-		filepath.Join(os.Getenv("HOME"), ".whisper", "models"),
-		filepath.Join(os.Getenv("HOME"), ".cache", "whisper"),
-		"/usr/share/whisper/models",
-		"/usr/local/share/whisper/models",
-	}
+// findModelPath returns the path of the model, or of its English-only
+// variant if english is set and only that is installed.
+func findModelPath(model string, english bool) string {
+	modelNames := models.CandidateFilenames(model, english)
 
-	modelNames := []string{
-		fmt.Sprintf("ggml-%s.bin", model),
-		fmt.Sprintf("ggml-%s.en.bin", model),
-		fmt.Sprintf("%s.bin", model),
-		fmt.Sprintf("ggml-model-%s.bin", model),
-	}
-
-	for _, basePath := range basePaths {
-		if basePath == "" {
-			continue
-		}
+	for _, basePath := range models.SearchPaths() {
 		for _, modelName := range modelNames {
 			fullPath := filepath.Join(basePath, modelName)
 			if _, err := os.Stat(fullPath); err == nil {
@@ -178,6 +237,44 @@ func normalizeTimestamp(ts string) string {
 	return strings.ReplaceAll(ts, ",", ".")
 }
 
+// ParseTimestamp converts a whisper timestamp (HH:MM:SS.mmm) to a duration.
+func ParseTimestamp(ts string) (time.Duration, error) {
+	ts = normalizeTimestamp(strings.TrimSpace(ts))
+	parts := strings.Split(ts, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid timestamp: %q", ts)
+	}
+
+	h, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid timestamp: %q", ts)
+	}
+	m, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, fmt.Errorf("invalid timestamp: %q", ts)
+	}
+	s, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timestamp: %q", ts)
+	}
+
+	total := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute
+	total += time.Duration(s * float64(time.Second))
+	return total.Round(time.Millisecond), nil
+}
+
+// StartTime returns the segment start as a duration.
+func (s Segment) StartTime() time.Duration {
+	d, _ := ParseTimestamp(s.Start)
+	return d
+}
+
+// EndTime returns the segment end as a duration.
+func (s Segment) EndTime() time.Duration {
+	d, _ := ParseTimestamp(s.End)
+	return d
+}
+
 func formatTimestamp(ts string) string {
 	ts = normalizeTimestamp(ts)
 	parts := strings.Split(ts, ":")
@@ -194,6 +291,37 @@ func formatTimestamp(ts string) string {
 	return "[" + ts + "]"
 }
 
+// ShiftSegments moves segments and their tokens later by offset, in place.
+func ShiftSegments(segments []Segment, offset time.Duration) []Segment {
+	if offset == 0 {
+		return segments
+	}
+	for i := range segments {
+		seg := &segments[i]
+		seg.Start = FormatTimestamp(seg.StartTime() + offset)
+		seg.End = FormatTimestamp(seg.EndTime() + offset)
+		seg.Timestamp = formatTimestamp(seg.Start)
+		for j := range seg.Tokens {
+			seg.Tokens[j].Start += offset
+			seg.Tokens[j].End += offset
+		}
+	}
+	return segments
+}
+
+// ClipSegments returns the segments that overlap the range from start to
+// end. Zero end means no upper bound.
+func ClipSegments(segments []Segment, start, end time.Duration) []Segment {
+	var out []Segment
+	for _, seg := range segments {
+		if seg.EndTime() <= start || (end > 0 && seg.StartTime() >= end) {
+			continue
+		}
+		out = append(out, seg)
+	}
+	return out
+}
+
 // CountWords counts words in segments.
 func CountWords(segments []Segment) int {
 	count := 0
@@ -204,9 +332,21 @@ func CountWords(segments []Segment) int {
 	return count
 }
 
+// FindModel returns the path of a locally available model, or "" if it is
+// not installed.
+func FindModel(model string) string {
+	return findModelPath(model, false)
+}
+
 // ModelExists checks if a whisper model is available locally.
 func ModelExists(model string) bool {
-	return findModelPath(model) != ""
+	return findModelPath(model, false) != ""
+}
+
+// EnglishOnlyFallback reports whether a multilingual model is installed
+// only as its English-only variant, which jobs that allow it use instead.
+func EnglishOnlyFallback(model string) bool {
+	return !IsEnglishOnly(model) && findModelPath(model, false) == "" && findModelPath(model, true) != ""
 }
 
 // ErrModelNotFound is returned when a model is not found locally.
@@ -219,8 +359,10 @@ func (e ErrModelNotFound) Error() string {
 }
 
 // CheckModel verifies the model exists, returning ErrModelNotFound if not.
-func CheckModel(model string) error {
-	if !ModelExists(model) {
+// If english is set, the model's English-only variant can run the job and
+// will do.
+func CheckModel(model string, english bool) error {
+	if findModelPath(model, english) == "" {
 		return ErrModelNotFound{Model: model}
 	}
 	return nil
@@ -0,0 +1,142 @@
+package transcriber
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Token is a single whisper token with timing and confidence.
+type Token struct {
+	Text        string
+	Start       time.Duration
+	End         time.Duration
+	Probability float64
+}
+
+// whisperOutput mirrors the file written by whisper.cpp --output-json and
+// --output-json-full. Tokens are only present in the full variant.
+type whisperOutput struct {
+	Result struct {
+		Language string `json:"language"`
+	} `json:"result"`
+	Transcription []struct {
+		Offsets whisperOffsets `json:"offsets"`
+		Text    string         `json:"text"`
+		// Speaker is set by --diarize: "0", "1", or "?" if undecided.
+		Speaker string `json:"speaker"`
+		// SpeakerTurnNext is only present with --tinydiarize; it is true
+		// when the speaker changes after this segment.
+		SpeakerTurnNext *bool `json:"speaker_turn_next"`
+		Tokens          []struct {
+			Text    string         `json:"text"`
+			Offsets whisperOffsets `json:"offsets"`
+			P       float64        `json:"p"`
+		} `json:"tokens"`
+	} `json:"transcription"`
+}
+
+// whisperOffsets holds start and end offsets in milliseconds.
+type whisperOffsets struct {
+	From int64 `json:"from"`
+	To   int64 `json:"to"`
+}
+
+// parseJSONOutput reads segments, token data and the detected language from
+// a whisper.cpp JSON file.
+func parseJSONOutput(path string) ([]Segment, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read whisper output: %w", err)
+	}
+	return parseJSON(data)
+}
+
+func parseJSON(data []byte) ([]Segment, error) {
+	var out whisperOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("parse whisper output: %w", err)
+	}
+	if out.Transcription == nil {
+		return nil, fmt.Errorf("parse whisper output: missing transcription")
+	}
+
+	var segments []Segment
+	// tinydiarize only marks speaker changes, so turns alternate between
+	// two labels.
+	turn := 0
+	for _, t := range out.Transcription {
+		text := strings.TrimSpace(t.Text)
+		turnNext := t.SpeakerTurnNext != nil && *t.SpeakerTurnNext
+		if text == "" {
+			if turnNext {
+				turn++
+			}
+			continue
+		}
+
+		start := time.Duration(t.Offsets.From) * time.Millisecond
+		end := time.Duration(t.Offsets.To) * time.Millisecond
+		seg := Segment{
+			Start:     FormatTimestamp(start),
+			End:       FormatTimestamp(end),
+			Text:      text,
+			Timestamp: formatTimestamp(FormatTimestamp(start)),
+			Language:  out.Result.Language,
+			Speaker:   stereoSpeaker(t.Speaker),
+		}
+		if t.SpeakerTurnNext != nil {
+			seg.Speaker = SpeakerLabel(turn % 2)
+		}
+		if turnNext {
+			turn++
+		}
+
+		for _, tok := range t.Tokens {
+			if isSpecialToken(tok.Text) {
+				continue
+			}
+			seg.Tokens = append(seg.Tokens, Token{
+				Text:        tok.Text,
+				Start:       time.Duration(tok.Offsets.From) * time.Millisecond,
+				End:         time.Duration(tok.Offsets.To) * time.Millisecond,
+				Probability: tok.P,
+			})
+		}
+
+		seg.Probability = meanProbability(seg.Tokens)
+		segments = append(segments, seg)
+	}
+
+	return segments, nil
+}
+
+// meanProbability averages token probabilities, returning 0 without tokens.
+func meanProbability(tokens []Token) float64 {
+	if len(tokens) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, tok := range tokens {
+		sum += tok.Probability
+	}
+	return sum / float64(len(tokens))
+}
+
+// isSpecialToken reports whether a token is a whisper control token such as
+// [_BEG_], [_TT_150] or <|endoftext|>.
+func isSpecialToken(text string) bool {
+	return strings.HasPrefix(text, "[_") || strings.HasPrefix(text, "<|")
+}
+
+// FormatTimestamp formats a duration as a whisper timestamp (HH:MM:SS.mmm).
+func FormatTimestamp(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d.%03d",
+		ms/3_600_000, (ms%3_600_000)/60_000, (ms%60_000)/1000, ms%1000)
+}
@@ -0,0 +1,96 @@
+package transcriber
+
+import (
+	"math"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestParseJSONOutputFull(t *testing.T) {
+	segments, err := parseJSONOutput("testdata/whisper_full.json")
+	if err != nil {
+		t.Fatalf("parseJSONOutput failed: %v", err)
+	}
+
+	if len(segments) != 2 {
+		t.Fatalf("expected 2 segments (blank dropped), got %d", len(segments))
+	}
+
+	first := segments[0]
+	if first.Text != "Guten Tag zusammen." {
+		t.Errorf("unexpected text: %q", first.Text)
+	}
+	if first.Start != "00:00:00.000" || first.End != "00:00:02.480" {
+		t.Errorf("unexpected times: %s-%s", first.Start, first.End)
+	}
+	if first.Language != "de" {
+		t.Errorf("expected detected language de, got %q", first.Language)
+	}
+	if len(first.Tokens) != 3 {
+		t.Fatalf("expected special tokens to be dropped, got %d tokens", len(first.Tokens))
+	}
+	if tok := first.Tokens[1]; tok.Text != " Tag" || tok.Start != 620*time.Millisecond || tok.End != 1100*time.Millisecond {
+		t.Errorf("unexpected token: %+v", tok)
+	}
+	if math.Abs(first.Probability-0.8) > 1e-9 {
+		t.Errorf("expected mean probability 0.8, got %v", first.Probability)
+	}
+
+	last := segments[1]
+	if last.Start != "01:02:03.450" || last.Timestamp != "[01:02:03]" {
+		t.Errorf("unexpected hour timestamps: %s %s", last.Start, last.Timestamp)
+	}
+	if last.EndTime() != time.Hour+2*time.Minute+7*time.Second {
+		t.Errorf("unexpected end time: %v", last.EndTime())
+	}
+}
+
+func TestParseJSONOutputWithoutTokens(t *testing.T) {
+	segments, err := parseJSONOutput("testdata/whisper_basic.json")
+	if err != nil {
+		t.Fatalf("parseJSONOutput failed: %v", err)
+	}
+
+	if len(segments) != 2 {
+		t.Fatalf("expected 2 segments, got %d", len(segments))
+	}
+	for _, seg := range segments {
+		if seg.Language != "en" || seg.Tokens != nil || seg.Probability != 0 {
+			t.Errorf("unexpected segment: %+v", seg)
+		}
+	}
+	if CountWords(segments) != 14 {
+		t.Errorf("expected 14 words, got %d", CountWords(segments))
+	}
+}
+
+func TestParseJSONOutputErrors(t *testing.T) {
+	if _, err := parseJSONOutput("testdata/missing.json"); err == nil {
+		t.Error("expected error for missing file")
+	}
+
+	path := t.TempDir() + "/bad.json"
+	if err := os.WriteFile(path, []byte(`{"result": {"language": "en"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parseJSONOutput(path); err == nil {
+		t.Error("expected error for output without transcription")
+	}
+}
+
+func TestShiftSegments(t *testing.T) {
+	segments, err := parseJSONOutput("testdata/whisper_full.json")
+	if err != nil {
+		t.Fatalf("parseJSONOutput failed: %v", err)
+	}
+
+	segments = ShiftSegments(segments, 90*time.Second)
+	first := segments[0]
+	if first.Start != "00:01:30.000" || first.End != "00:01:32.480" || first.Timestamp != "[01:30]" {
+		t.Errorf("unexpected shifted times: %s-%s %s", first.Start, first.End, first.Timestamp)
+	}
+	if tok := first.Tokens[1]; tok.Start != 90*time.Second+620*time.Millisecond {
+		t.Errorf("token not shifted: %+v", tok)
+	}
+}
@@ -5,58 +5,64 @@ import (
 	"os/exec"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/cyber/whisper-transcribe/internal/config"
+	"github.com/cyber/whisper-transcribe/internal/history"
 	"github.com/cyber/whisper-transcribe/internal/models"
 	"github.com/cyber/whisper-transcribe/internal/pipeline"
+	"github.com/cyber/whisper-transcribe/internal/queue"
 	"github.com/cyber/whisper-transcribe/internal/transcriber"
 )
 
-// RunPipeline creates a command that runs the transcription pipeline.
-func RunPipeline(cfg *config.TranscriptionConfig, program *tea.Program) tea.Cmd {
-	return func() tea.Msg {
-		events := make(chan pipeline.Event, 100)
-
-		go func() {
-			p := pipeline.New(cfg, events)
-			p.Run()
-			close(events)
-		}()
-
-		go func() {
-			for event := range events {
-				switch e := event.(type) {
-				case pipeline.MetadataEvent:
-					program.Send(MetadataFetchedMsg{
-						Title:    e.Title,
-						Channel:  e.Channel,
-						Duration: e.Duration,
-					})
-				case pipeline.ProgressEvent:
-					program.Send(PipelineProgressMsg{
-						Step:     e.Step,
-						Progress: e.Progress,
-						Message:  e.Message,
-					})
-				case pipeline.TranscriptEvent:
-					program.Send(TranscriptChunkMsg{
-						Text:      e.Text,
-						Timestamp: e.Timestamp,
-					})
-				case pipeline.CompletedEvent:
-					program.Send(PipelineCompletedMsg{
-						OutputPath: e.OutputPath,
-						Stats:      e.Stats,
-					})
-				case pipeline.ErrorEvent:
-					program.Send(PipelineErrorMsg{
-						Step: e.Step,
-						Err:  e.Err,
-					})
-				}
-			}
-		}()
+// forwardEvents turns queue events into TUI messages until the queue closes.
+func forwardEvents(q *queue.Queue, program *tea.Program) {
+	for qe := range q.Events() {
+		switch e := qe.Event.(type) {
+		case pipeline.MetadataEvent:
+			program.Send(MetadataFetchedMsg{
+				JobID:    qe.JobID,
+				Title:    e.Title,
+				Channel:  e.Channel,
+				Duration: e.Duration,
+			})
+		case pipeline.ProgressEvent:
+			program.Send(PipelineProgressMsg{
+				JobID:    qe.JobID,
+				Step:     e.Step,
+				Progress: e.Progress,
+				Message:  e.Message,
+			})
+		case pipeline.TranscriptEvent:
+			program.Send(TranscriptChunkMsg{
+				JobID:     qe.JobID,
+				Text:      e.Text,
+				Timestamp: e.Timestamp,
+			})
+		case pipeline.CompletedEvent:
+			program.Send(PipelineCompletedMsg{
+				JobID:       qe.JobID,
+				OutputPath:  e.OutputPath,
+				OutputPaths: e.OutputPaths,
+				Stats:       e.Stats,
+			})
+		case pipeline.ErrorEvent:
+			program.Send(PipelineErrorMsg{
+				JobID: qe.JobID,
+				Step:  e.Step,
+				Err:   e.Err,
+			})
+		case pipeline.CancelledEvent:
+			program.Send(PipelineCancelledMsg{
+				JobID: qe.JobID,
+				Step:  e.Step,
+			})
+		}
+	}
+}
 
-		return PipelineStartedMsg{}
+// LoadHistory creates a command that reads the transcription history.
+func LoadHistory(store *history.Store) tea.Cmd {
+	return func() tea.Msg {
+		entries, err := store.List()
+		return HistoryLoadedMsg{Entries: entries, Err: err}
 	}
 }
 
@@ -72,10 +78,11 @@ func OpenInEditor(path string) tea.Cmd {
 	})
 }
 
-// CheckModel verifies if a model exists locally.
-func CheckModel(model string) tea.Cmd {
+// CheckModel verifies if a model exists locally. english allows its
+// English-only variant, for jobs that it can run.
+func CheckModel(model string, english bool) tea.Cmd {
 	return func() tea.Msg {
-		if err := transcriber.CheckModel(model); err != nil {
+		if err := transcriber.CheckModel(model, english); err != nil {
 			if _, ok := err.(transcriber.ErrModelNotFound); ok {
 				info, _ := models.GetModelInfo(model)
 				size := "unknown"
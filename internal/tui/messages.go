@@ -1,6 +1,10 @@
 package tui
 
-import "github.com/cyber/whisper-transcribe/internal/pipeline"
+import (
+	"github.com/cyber/whisper-transcribe/internal/history"
+	"github.com/cyber/whisper-transcribe/internal/pipeline"
+	"github.com/cyber/whisper-transcribe/internal/queue"
+)
 
 // Screen represents the current TUI screen.
 type Screen int
@@ -10,16 +14,15 @@ const (
 	ModelDownloadScreen
 	ProgressScreen
 	PreviewScreen
+	HistoryScreen
 )
 
 // ScreenMsg triggers a screen transition.
 type ScreenMsg Screen
 
-// PipelineStartedMsg signals the pipeline has started.
-type PipelineStartedMsg struct{}
-
 // MetadataFetchedMsg contains video metadata.
 type MetadataFetchedMsg struct {
+	JobID    queue.JobID
 	Title    string
 	Channel  string
 	Duration string
@@ -27,6 +30,7 @@ type MetadataFetchedMsg struct {
 
 // PipelineProgressMsg reports step progress.
 type PipelineProgressMsg struct {
+	JobID    queue.JobID
 	Step     string
 	Progress float64
 	Message  string
@@ -34,20 +38,37 @@ type PipelineProgressMsg struct {
 
 // TranscriptChunkMsg streams transcription text.
 type TranscriptChunkMsg struct {
+	JobID     queue.JobID
 	Text      string
 	Timestamp string
 }
 
 // PipelineCompletedMsg signals successful completion.
 type PipelineCompletedMsg struct {
-	OutputPath string
-	Stats      pipeline.Stats
+	JobID       queue.JobID
+	OutputPath  string
+	OutputPaths []string
+	Stats       pipeline.Stats
 }
 
 // PipelineErrorMsg signals a pipeline error.
 type PipelineErrorMsg struct {
-	Step string
-	Err  error
+	JobID queue.JobID
+	Step  string
+	Err   error
+}
+
+// PipelineCancelledMsg signals that a cancelled pipeline has stopped and
+// cleaned up.
+type PipelineCancelledMsg struct {
+	JobID queue.JobID
+	Step  string
+}
+
+// HistoryLoadedMsg carries the transcription history.
+type HistoryLoadedMsg struct {
+	Entries []history.Entry
+	Err     error
 }
 
 // EditorClosedMsg signals the external editor has closed.
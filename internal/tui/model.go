@@ -2,7 +2,13 @@ package tui
 
 import (
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cyber/whisper-transcribe/internal/cache"
 	"github.com/cyber/whisper-transcribe/internal/config"
+	"github.com/cyber/whisper-transcribe/internal/formatter"
+	"github.com/cyber/whisper-transcribe/internal/history"
+	"github.com/cyber/whisper-transcribe/internal/models"
+	"github.com/cyber/whisper-transcribe/internal/pipeline"
+	"github.com/cyber/whisper-transcribe/internal/queue"
 	"github.com/cyber/whisper-transcribe/internal/tui/screens"
 	"github.com/cyber/whisper-transcribe/internal/tui/styles"
 )
@@ -17,10 +23,14 @@ type Model struct {
 	download *screens.DownloadModel
 	progress *screens.ProgressModel
 	preview  *screens.PreviewModel
+	history  *screens.HistoryModel
 
 	width  int
 	height int
 
+	queue          *queue.Queue
+	store          *history.Store
+	jobID          queue.JobID
 	pipelineActive bool
 	pendingConfig  *config.TranscriptionConfig
 
@@ -30,6 +40,7 @@ type Model struct {
 // NewModel creates a new root TUI model.
 func NewModel(cfg *config.Config) *Model {
 	theme := styles.NewTheme()
+	store := history.Open(cfg.HistoryFile)
 	return &Model{
 		config:   cfg,
 		screen:   InputScreen,
@@ -38,12 +49,28 @@ func NewModel(cfg *config.Config) *Model {
 		download: screens.NewDownloadModel(theme),
 		progress: screens.NewProgressModel(theme),
 		preview:  screens.NewPreviewModel(theme),
+		history:  screens.NewHistoryModel(theme),
+		store:    store,
+		queue: queue.New(queue.Options{
+			Workers:   cfg.Workers,
+			Downloads: cfg.MaxDownloads,
+			History:   store,
+			Cache:     cache.Open(cfg.CacheDir),
+		}),
 	}
 }
 
 // SetProgram sets the program reference for external message injection.
 func (m *Model) SetProgram(p *tea.Program) {
 	m.program = p
+	go forwardEvents(m.queue, p)
+}
+
+// Shutdown cancels any running job and waits until it has stopped its tools
+// and removed its temporary files. Call it after the program has exited.
+func (m *Model) Shutdown() {
+	m.queue.CancelAll()
+	m.queue.Wait()
 }
 
 // Init initializes the root model.
@@ -55,6 +82,11 @@ func (m Model) Init() tea.Cmd {
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
+	// Drop late events from jobs that are no longer shown.
+	if id, ok := jobIDOf(msg); ok && id != 0 && id != m.jobID {
+		return m, nil
+	}
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -63,17 +95,34 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.download.SetSize(msg.Width, msg.Height)
 		m.progress.SetSize(msg.Width, msg.Height)
 		m.preview.SetSize(msg.Width, msg.Height)
+		m.history.SetSize(msg.Width, msg.Height)
 
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c":
+			// Running jobs are cancelled by Shutdown once the program exits.
 			return m, tea.Quit
+		case "esc":
+			if m.screen == ProgressScreen && m.pipelineActive && !m.progress.Cancelling() {
+				m.queue.Cancel(m.jobID)
+				m.progress.SetCancelling()
+				return m, nil
+			}
 		case "q":
-			if !m.pipelineActive && m.screen != ProgressScreen && m.screen != ModelDownloadScreen {
+			if !m.pipelineActive && m.screen != ProgressScreen && m.screen != ModelDownloadScreen && m.screen != HistoryScreen {
 				return m, tea.Quit
 			}
+		case "ctrl+r":
+			if m.screen == InputScreen {
+				m.screen = HistoryScreen
+				m.history.Reset()
+				return m, LoadHistory(m.store)
+			}
 		}
 
+	case HistoryLoadedMsg:
+		m.history.SetEntries(msg.Entries, msg.Err)
+
 	case ScreenMsg:
 		m.screen = Screen(msg)
 
@@ -94,9 +143,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		model, cmd := m.download.Update(screens.DownloadCompleteMsg{})
 		m.download = model.(*screens.DownloadModel)
 		cmds = append(cmds, cmd)
-		// Continue with pipeline after short delay
+		// Continue with the pipeline now that the model is installed
 		if m.pendingConfig != nil {
-			cmds = append(cmds, RunPipeline(m.pendingConfig, m.program))
+			cmds = append(cmds, m.startPipeline(m.pendingConfig))
 		}
 
 	case ModelDownloadErrorMsg:
@@ -104,11 +153,6 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.download = model.(*screens.DownloadModel)
 		cmds = append(cmds, cmd)
 
-	case PipelineStartedMsg:
-		m.screen = ProgressScreen
-		m.pipelineActive = true
-		cmds = append(cmds, m.progress.Init())
-
 	case MetadataFetchedMsg:
 		model, cmd := m.progress.Update(screens.MetadataMsg{
 			Title:    msg.Title,
@@ -139,7 +183,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.screen = PreviewScreen
 		m.pipelineActive = false
 		m.pendingConfig = nil
-		m.preview.SetResult(msg.OutputPath, msg.Stats)
+		m.preview.SetResult(msg.OutputPath, msg.OutputPaths, msg.Stats)
 
 	case PipelineErrorMsg:
 		m.pipelineActive = false
@@ -150,6 +194,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.progress = model.(*screens.ProgressModel)
 		cmds = append(cmds, cmd)
 
+	case PipelineCancelledMsg:
+		// Back to the input, which still holds the cancelled job's settings.
+		m.screen = InputScreen
+		m.pipelineActive = false
+		m.pendingConfig = nil
+		m.progress.Reset()
+		return m, m.input.Init()
+
 	case EditorClosedMsg:
 		// Editor closed, no action needed
 	}
@@ -162,10 +214,29 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		if m.input.Submitted() {
 			cfg := m.input.GetConfig()
-			m.pendingConfig = cfg
+			if cfg.Model == models.Auto {
+				cfg.Model, _ = models.SelectAuto(models.DetectResources(), m.config.AutoTargetRTF)
+			}
+			// The profile was checked when the config was loaded.
+			cfg.Whisper, _ = m.config.WhisperProfile("")
+			cfg.Vocabulary = m.config.Vocabulary
+			cfg.Diarize = m.config.Diarize
+			cfg.Speakers = m.config.Speakers
+			cfg.Captions = m.config.Captions
+			cfg.Audio = m.config.Audio
+			cfg.ChunkLength = m.config.ChunkLength
+			cfg.ChunkWorkers = m.config.ChunkWorkers
+			cfg.KeepAudio = m.config.KeepAudio
 			m.input.ClearSubmitted()
-			// Check if model exists before running pipeline
-			cmds = append(cmds, CheckModel(cfg.Model))
+			// The settings from the config file are checked against the
+			// form's only now that they are put together.
+			if err := cfg.Validate(); err != nil {
+				m.input.SetError(err)
+			} else {
+				m.pendingConfig = cfg
+				// Check if model exists before running pipeline
+				cmds = append(cmds, CheckModel(cfg.Model, cfg.EnglishOnlyFits()))
+			}
 		}
 
 	case ModelDownloadScreen:
@@ -214,16 +285,68 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.preview.OpenEdit() {
 			cmds = append(cmds, OpenInEditor(m.preview.GetOutputPath()))
 		}
+
+	case HistoryScreen:
+		model, cmd := m.history.Update(msg)
+		m.history = model.(*screens.HistoryModel)
+		cmds = append(cmds, cmd)
+
+		if m.history.Back() {
+			m.screen = InputScreen
+			return m, m.input.Init()
+		}
+
+		if e := m.history.OpenEdit(); e != nil && len(e.OutputPaths) > 0 {
+			cmds = append(cmds, OpenInEditor(formatter.PrimaryOutput(e.OutputPaths)))
+		}
+
+		if e := m.history.Selected(); e != nil && len(e.OutputPaths) > 0 {
+			m.screen = PreviewScreen
+			m.preview.SetResult(formatter.PrimaryOutput(e.OutputPaths), e.OutputPaths, pipeline.Stats{
+				Duration:  e.Metadata.Duration,
+				WordCount: e.WordCount,
+				Model:     e.Model,
+			})
+		}
 	}
 
 	// If model check passed (nil message), run pipeline
 	if msg == nil && m.pendingConfig != nil && m.screen == InputScreen {
-		cmds = append(cmds, RunPipeline(m.pendingConfig, m.program))
+		cmds = append(cmds, m.startPipeline(m.pendingConfig))
 	}
 
 	return m, tea.Batch(cmds...)
 }
 
+// startPipeline submits a job and shows its progress. The job's ID is set
+// before any of its events can arrive, so that none of them are dropped
+// as belonging to another job.
+func (m *Model) startPipeline(cfg *config.TranscriptionConfig) tea.Cmd {
+	m.jobID = m.queue.Submit(cfg)
+	m.screen = ProgressScreen
+	m.pipelineActive = true
+	return m.progress.Init()
+}
+
+// jobIDOf returns the job a pipeline message belongs to.
+func jobIDOf(msg tea.Msg) (queue.JobID, bool) {
+	switch msg := msg.(type) {
+	case MetadataFetchedMsg:
+		return msg.JobID, true
+	case PipelineProgressMsg:
+		return msg.JobID, true
+	case TranscriptChunkMsg:
+		return msg.JobID, true
+	case PipelineCompletedMsg:
+		return msg.JobID, true
+	case PipelineErrorMsg:
+		return msg.JobID, true
+	case PipelineCancelledMsg:
+		return msg.JobID, true
+	}
+	return 0, false
+}
+
 // View renders the current screen.
 func (m Model) View() string {
 	switch m.screen {
@@ -235,6 +358,8 @@ func (m Model) View() string {
 		return m.progress.View()
 	case PreviewScreen:
 		return m.preview.View()
+	case HistoryScreen:
+		return m.history.View()
 	default:
 		return ""
 	}
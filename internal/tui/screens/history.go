@@ -0,0 +1,235 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cyber/whisper-transcribe/internal/history"
+	"github.com/cyber/whisper-transcribe/internal/tui/styles"
+)
+
+// HistoryModel handles the history screen listing past transcriptions.
+type HistoryModel struct {
+	theme  *styles.Theme
+	search textinput.Model
+
+	entries  []history.Entry
+	filtered []history.Entry
+	err      error
+	cursor   int
+	offset   int
+
+	selected *history.Entry
+	back     bool
+	openEdit bool
+
+	width  int
+	height int
+}
+
+// NewHistoryModel creates a new history screen model.
+func NewHistoryModel(theme *styles.Theme) *HistoryModel {
+	si := textinput.New()
+	si.Placeholder = "title, channel, source or model"
+	si.Prompt = "/ "
+	si.CharLimit = 100
+	si.Width = 40
+
+	return &HistoryModel{
+		theme:  theme,
+		search: si,
+	}
+}
+
+// Init initializes the history model.
+func (m *HistoryModel) Init() tea.Cmd {
+	return nil
+}
+
+// SetEntries replaces the listed entries.
+func (m *HistoryModel) SetEntries(entries []history.Entry, err error) {
+	m.entries = entries
+	m.err = err
+	m.filter()
+}
+
+// Update handles history events.
+func (m *HistoryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	if m.search.Focused() {
+		switch keyMsg.String() {
+		case "esc":
+			m.search.SetValue("")
+			m.search.Blur()
+			m.filter()
+		case "enter", "down":
+			m.search.Blur()
+		default:
+			m.search, cmd = m.search.Update(msg)
+			m.filter()
+		}
+		return m, cmd
+	}
+
+	switch keyMsg.String() {
+	case "up", "k":
+		m.cursor = max(0, m.cursor-1)
+	case "down", "j":
+		m.cursor = min(len(m.filtered)-1, m.cursor+1)
+	case "/":
+		cmd = m.search.Focus()
+	case "enter":
+		if m.cursor < len(m.filtered) {
+			m.selected = &m.filtered[m.cursor]
+		}
+	case "e":
+		if m.cursor < len(m.filtered) {
+			m.openEdit = true
+		}
+	case "esc":
+		m.back = true
+	}
+	m.scroll()
+
+	return m, cmd
+}
+
+func (m *HistoryModel) filter() {
+	m.filtered = m.filtered[:0]
+	query := m.search.Value()
+	for _, e := range m.entries {
+		if e.Matches(query) {
+			m.filtered = append(m.filtered, e)
+		}
+	}
+	m.cursor = max(0, min(m.cursor, len(m.filtered)-1))
+	m.scroll()
+}
+
+// scroll keeps the cursor inside the visible rows.
+func (m *HistoryModel) scroll() {
+	rows := m.visibleRows()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+rows {
+		m.offset = m.cursor - rows + 1
+	}
+}
+
+func (m *HistoryModel) visibleRows() int {
+	return max(3, m.height-12)
+}
+
+// View renders the history screen.
+func (m *HistoryModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(m.theme.Title.Render("Transcription History"))
+	b.WriteString("\n")
+	b.WriteString("  ")
+	b.WriteString(m.search.View())
+	b.WriteString("\n\n")
+
+	switch {
+	case m.err != nil:
+		b.WriteString("  ")
+		b.WriteString(m.theme.Error.Render(m.err.Error()))
+		b.WriteString("\n")
+	case len(m.entries) == 0:
+		b.WriteString(m.theme.Dim.Render("  No transcriptions yet."))
+		b.WriteString("\n")
+	case len(m.filtered) == 0:
+		b.WriteString(m.theme.Dim.Render("  No matches."))
+		b.WriteString("\n")
+	}
+
+	titleWidth := max(20, m.width-40)
+	end := min(len(m.filtered), m.offset+m.visibleRows())
+	for i := m.offset; i < end; i++ {
+		e := m.filtered[i]
+		line := fmt.Sprintf("%s  %-8s %6d  %s",
+			e.CompletedAt.Local().Format("2006-01-02 15:04"),
+			e.Model,
+			e.WordCount,
+			truncate(e.Title(), titleWidth))
+		if i == m.cursor {
+			b.WriteString(m.theme.Primary.Render("▶ " + line))
+		} else {
+			b.WriteString(m.theme.Dim.Render("  " + line))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	if m.cursor < len(m.filtered) {
+		b.WriteString(m.theme.Dim.Render("  " + strings.Join(m.filtered[m.cursor].OutputPaths, ", ")))
+		b.WriteString("\n\n")
+	}
+
+	help := m.theme.Help.Render("↑/↓ navigate • / search • enter preview • e edit • esc back")
+	b.WriteString(help)
+
+	return b.String()
+}
+
+// Selected returns the entry chosen for preview, if any.
+func (m *HistoryModel) Selected() *history.Entry {
+	e := m.selected
+	m.selected = nil
+	return e
+}
+
+// Back returns true if user wants to leave the history screen.
+func (m *HistoryModel) Back() bool {
+	if m.back {
+		m.back = false
+		return true
+	}
+	return false
+}
+
+// OpenEdit returns the entry to open in the editor, if requested.
+func (m *HistoryModel) OpenEdit() *history.Entry {
+	if m.openEdit {
+		m.openEdit = false
+		return &m.filtered[m.cursor]
+	}
+	return nil
+}
+
+// SetSize updates the screen dimensions.
+func (m *HistoryModel) SetSize(w, h int) {
+	m.width = w
+	m.height = h
+	m.search.Width = min(40, w-10)
+	m.scroll()
+}
+
+// Reset clears the search and selection.
+func (m *HistoryModel) Reset() {
+	m.search.SetValue("")
+	m.search.Blur()
+	m.cursor = 0
+	m.offset = 0
+	m.selected = nil
+	m.back = false
+	m.openEdit = false
+	m.filter()
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
@@ -4,12 +4,15 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/cyber/whisper-transcribe/internal/config"
 	"github.com/cyber/whisper-transcribe/internal/downloader"
+	"github.com/cyber/whisper-transcribe/internal/models"
+	"github.com/cyber/whisper-transcribe/internal/transcriber"
 	"github.com/cyber/whisper-transcribe/internal/tui/styles"
 )
 
@@ -21,13 +24,30 @@ const (
 	SourceLocalFile
 )
 
+// Fields of the input screen, in focus order.
+const (
+	focusSource = iota
+	focusInput
+	focusModel
+	focusLanguage
+	focusTranslate
+	focusTimestamps
+	focusClipStart
+	focusClipEnd
+	focusStart
+	focusCount
+)
+
 // InputModel handles the URL input and configuration screen.
 type InputModel struct {
-	theme      *styles.Theme
-	urlInput   textinput.Model
-	fileInput  textinput.Model
-	submitted  bool
-	err        error
+	theme     *styles.Theme
+	urlInput  textinput.Model
+	fileInput textinput.Model
+	// clipStart and clipEnd limit transcription to part of the media.
+	clipStart textinput.Model
+	clipEnd   textinput.Model
+	submitted bool
+	err       error
 
 	sourceType SourceType
 	url        string
@@ -35,9 +55,13 @@ type InputModel struct {
 	model      string
 	timestamps bool
 	outputDir  string
+	formats    []string
+	language   string
+	translate  bool
 
 	focusIndex int
 	models     []string
+	languages  []string
 
 	width  int
 	height int
@@ -60,15 +84,30 @@ func NewInputModel(theme *styles.Theme, cfg *config.Config) *InputModel {
 		theme:      theme,
 		urlInput:   ti,
 		fileInput:  fi,
+		clipStart:  newClipInput("start"),
+		clipEnd:    newClipInput("end"),
 		sourceType: SourceURL,
 		model:      cfg.DefaultModel,
 		outputDir:  cfg.OutputDir,
 		timestamps: cfg.Timestamps,
+		formats:    cfg.OutputFormats,
+		language:   cfg.Language,
+		translate:  cfg.Task == config.TaskTranslate,
 		models:     config.ModelOptions(),
-		focusIndex: 0,
+		languages:  config.LanguageOptions(),
+		focusIndex: focusSource,
 	}
 }
 
+// newClipInput creates a field for a time such as 1:30:00.
+func newClipInput(placeholder string) textinput.Model {
+	ci := textinput.New()
+	ci.Placeholder = placeholder
+	ci.CharLimit = 12
+	ci.Width = 12
+	return ci
+}
+
 // Init initializes the input model.
 func (m *InputModel) Init() tea.Cmd {
 	return textinput.Blink
@@ -82,75 +121,87 @@ func (m *InputModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "tab", "down":
-			m.focusIndex = (m.focusIndex + 1) % 5
+			m.focusIndex = (m.focusIndex + 1) % focusCount
 			m.updateFocus()
 		case "shift+tab", "up":
-			m.focusIndex = (m.focusIndex - 1 + 5) % 5
+			m.focusIndex = (m.focusIndex - 1 + focusCount) % focusCount
 			m.updateFocus()
 		case "left":
-			if m.focusIndex == 0 {
+			switch m.focusIndex {
+			case focusSource:
 				// Toggle source type
 				if m.sourceType == SourceLocalFile {
 					m.sourceType = SourceURL
 				}
-			} else if m.focusIndex == 2 {
-				idx := indexOf(m.models, m.model)
-				if idx > 0 {
-					m.model = m.models[idx-1]
-				}
+			case focusModel:
+				m.model = step(m.models, m.model, -1)
+			case focusLanguage:
+				m.language = step(m.languages, m.language, -1)
 			}
 		case "right":
-			if m.focusIndex == 0 {
+			switch m.focusIndex {
+			case focusSource:
 				// Toggle source type
 				if m.sourceType == SourceURL {
 					m.sourceType = SourceLocalFile
 				}
-			} else if m.focusIndex == 2 {
-				idx := indexOf(m.models, m.model)
-				if idx < len(m.models)-1 {
-					m.model = m.models[idx+1]
-				}
+			case focusModel:
+				m.model = step(m.models, m.model, 1)
+			case focusLanguage:
+				m.language = step(m.languages, m.language, 1)
 			}
 		case " ":
-			if m.focusIndex == 0 {
+			switch m.focusIndex {
+			case focusSource:
 				// Toggle source type
 				if m.sourceType == SourceURL {
 					m.sourceType = SourceLocalFile
 				} else {
 					m.sourceType = SourceURL
 				}
-			} else if m.focusIndex == 3 {
+			case focusTranslate:
+				m.translate = !m.translate
+			case focusTimestamps:
 				m.timestamps = !m.timestamps
 			}
 		case "enter":
-			if m.focusIndex == 4 {
-				if m.sourceType == SourceURL {
+			if m.focusIndex == focusStart {
+				if _, _, err := m.clipRange(); err != nil {
+					m.err = err
+				} else if m.sourceType == SourceURL {
 					m.url = m.urlInput.Value()
 					if err := downloader.ValidateURL(m.url); err != nil {
 						m.err = err
+					} else if downloader.IsPlaylistURL(m.url) {
+						m.err = fmt.Errorf("playlist and channel URLs are supported in CLI mode only")
 					} else {
-						m.err = nil
-						m.submitted = true
+						m.err = m.GetConfig().Validate()
+						m.submitted = m.err == nil
 					}
 				} else {
 					m.localFile = m.fileInput.Value()
 					if err := validateLocalFile(m.localFile); err != nil {
 						m.err = err
 					} else {
-						m.err = nil
-						m.submitted = true
+						m.err = m.GetConfig().Validate()
+						m.submitted = m.err == nil
 					}
 				}
 			}
 		}
 	}
 
-	if m.focusIndex == 1 {
+	switch m.focusIndex {
+	case focusInput:
 		if m.sourceType == SourceURL {
 			m.urlInput, cmd = m.urlInput.Update(msg)
 		} else {
 			m.fileInput, cmd = m.fileInput.Update(msg)
 		}
+	case focusClipStart:
+		m.clipStart, cmd = m.clipStart.Update(msg)
+	case focusClipEnd:
+		m.clipEnd, cmd = m.clipEnd.Update(msg)
 	}
 
 	return m, cmd
@@ -159,16 +210,34 @@ func (m *InputModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m *InputModel) updateFocus() {
 	m.urlInput.Blur()
 	m.fileInput.Blur()
+	m.clipStart.Blur()
+	m.clipEnd.Blur()
 
-	if m.focusIndex == 1 {
+	switch m.focusIndex {
+	case focusInput:
 		if m.sourceType == SourceURL {
 			m.urlInput.Focus()
 		} else {
 			m.fileInput.Focus()
 		}
+	case focusClipStart:
+		m.clipStart.Focus()
+	case focusClipEnd:
+		m.clipEnd.Focus()
 	}
 }
 
+// clipRange parses the time range fields. Empty fields are zero.
+func (m *InputModel) clipRange() (start, end time.Duration, err error) {
+	if start, err = config.ParseClipTime(m.clipStart.Value()); err != nil {
+		return 0, 0, fmt.Errorf("start: %w", err)
+	}
+	if end, err = config.ParseClipTime(m.clipEnd.Value()); err != nil {
+		return 0, 0, fmt.Errorf("end: %w", err)
+	}
+	return start, end, nil
+}
+
 // View renders the input screen.
 func (m *InputModel) View() string {
 	var b strings.Builder
@@ -179,7 +248,7 @@ func (m *InputModel) View() string {
 
 	// Source type selector
 	sourceLabel := "Source Type"
-	if m.focusIndex == 0 {
+	if m.focusIndex == focusSource {
 		sourceLabel = m.theme.Primary.Render("▶ " + sourceLabel)
 	} else {
 		sourceLabel = m.theme.Dim.Render("  " + sourceLabel)
@@ -202,7 +271,7 @@ func (m *InputModel) View() string {
 	} else {
 		inputLabel = "Audio File Path"
 	}
-	if m.focusIndex == 1 {
+	if m.focusIndex == focusInput {
 		inputLabel = m.theme.Primary.Render("▶ " + inputLabel)
 	} else {
 		inputLabel = m.theme.Dim.Render("  " + inputLabel)
@@ -225,24 +294,44 @@ func (m *InputModel) View() string {
 	b.WriteString("\n")
 
 	modelLabel := "Model"
-	if m.focusIndex == 2 {
+	if m.focusIndex == focusModel {
 		modelLabel = m.theme.Primary.Render("▶ " + modelLabel)
 	} else {
 		modelLabel = m.theme.Dim.Render("  " + modelLabel)
 	}
 	b.WriteString(modelLabel)
 	b.WriteString("\n  ")
-	for _, model := range m.models {
-		if model == m.model {
-			b.WriteString(m.theme.Accent.Render("◉ " + model + "  "))
-		} else {
-			b.WriteString(m.theme.Dim.Render("○ " + model + "  "))
-		}
+	b.WriteString(m.modelSelector())
+	b.WriteString("\n\n")
+
+	langLabel := "Language"
+	if m.focusIndex == focusLanguage {
+		langLabel = m.theme.Primary.Render("▶ " + langLabel)
+	} else {
+		langLabel = m.theme.Dim.Render("  " + langLabel)
+	}
+	b.WriteString(langLabel)
+	b.WriteString("  ")
+	b.WriteString(m.languageSelector())
+	b.WriteString("\n\n")
+
+	trLabel := "Translate to English"
+	if m.focusIndex == focusTranslate {
+		trLabel = m.theme.Primary.Render("▶ " + trLabel)
+	} else {
+		trLabel = m.theme.Dim.Render("  " + trLabel)
+	}
+	b.WriteString(trLabel)
+	b.WriteString("  ")
+	if m.translate {
+		b.WriteString(m.theme.Success.Render("☑ Yes"))
+	} else {
+		b.WriteString(m.theme.Dim.Render("☐ No"))
 	}
 	b.WriteString("\n\n")
 
 	tsLabel := "Include Timestamps"
-	if m.focusIndex == 3 {
+	if m.focusIndex == focusTimestamps {
 		tsLabel = m.theme.Primary.Render("▶ " + tsLabel)
 	} else {
 		tsLabel = m.theme.Dim.Render("  " + tsLabel)
@@ -256,11 +345,25 @@ func (m *InputModel) View() string {
 	}
 	b.WriteString("\n\n")
 
-	b.WriteString(m.theme.Dim.Render(fmt.Sprintf("  Output: %s", m.outputDir)))
+	rangeLabel := "Time Range"
+	if m.focusIndex == focusClipStart || m.focusIndex == focusClipEnd {
+		rangeLabel = m.theme.Primary.Render("▶ " + rangeLabel)
+	} else {
+		rangeLabel = m.theme.Dim.Render("  " + rangeLabel)
+	}
+	b.WriteString(rangeLabel)
+	b.WriteString("  ")
+	b.WriteString(m.clipStart.View())
+	b.WriteString(m.theme.Dim.Render(" to "))
+	b.WriteString(m.clipEnd.View())
+	b.WriteString(m.theme.Dim.Render("  (empty for the whole media)"))
+	b.WriteString("\n\n")
+
+	b.WriteString(m.theme.Dim.Render(fmt.Sprintf("  Output: %s (%s)", m.outputDir, strings.Join(m.formats, ", "))))
 	b.WriteString("\n\n")
 
 	startBtn := "[ Start Transcription ]"
-	if m.focusIndex == 4 {
+	if m.focusIndex == focusStart {
 		startBtn = m.theme.ButtonActive.Render(startBtn)
 	} else {
 		startBtn = m.theme.Button.Render(startBtn)
@@ -268,12 +371,65 @@ func (m *InputModel) View() string {
 	b.WriteString(lipgloss.NewStyle().MarginLeft(20).Render(startBtn))
 	b.WriteString("\n\n")
 
-	help := m.theme.Help.Render("↑/↓ navigate • ←/→ select • space toggle • enter submit • q quit")
+	help := m.theme.Help.Render("↑/↓ navigate • ←/→ select • space toggle • enter submit • ctrl+r history • q quit")
 	b.WriteString(help)
 
 	return b.String()
 }
 
+// modelSelectorWidth is the number of models shown around the selection.
+const modelSelectorWidth = 5
+
+// modelSelector renders a window of the model list around the selected
+// model, since the full list of variants doesn't fit on one line.
+func (m *InputModel) modelSelector() string {
+	var b strings.Builder
+
+	idx := max(0, indexOf(m.models, m.model))
+	start := max(0, min(idx-modelSelectorWidth/2, len(m.models)-modelSelectorWidth))
+	end := min(len(m.models), start+modelSelectorWidth)
+
+	if start > 0 {
+		b.WriteString(m.theme.Dim.Render("‹ "))
+	}
+	for _, model := range m.models[start:end] {
+		if model == m.model {
+			b.WriteString(m.theme.Accent.Render("◉ " + model + "  "))
+		} else {
+			b.WriteString(m.theme.Dim.Render("○ " + model + "  "))
+		}
+	}
+	if end < len(m.models) {
+		b.WriteString(m.theme.Dim.Render("›"))
+	}
+
+	if m.model == models.Auto {
+		b.WriteString(m.theme.Dim.Render("\n  Picks the largest model that fits this machine"))
+	} else if info, err := models.GetModelInfo(m.model); err == nil {
+		b.WriteString(m.theme.Dim.Render(fmt.Sprintf("\n  %s (%d of %d)", info.Size, idx+1, len(m.models))))
+	}
+	return b.String()
+}
+
+// languageSelector renders the selected language between arrows; the full
+// list of whisper languages is far too long to show.
+func (m *InputModel) languageSelector() string {
+	idx := indexOf(m.languages, m.language)
+	var b strings.Builder
+	if idx > 0 {
+		b.WriteString(m.theme.Dim.Render("‹ "))
+	}
+	label := m.language
+	if label == transcriber.LanguageAuto {
+		label = "auto (detect)"
+	}
+	b.WriteString(m.theme.Accent.Render(label))
+	if idx < len(m.languages)-1 {
+		b.WriteString(m.theme.Dim.Render(" ›"))
+	}
+	return b.String()
+}
+
 // Submitted returns true if the form has been submitted.
 func (m *InputModel) Submitted() bool {
 	return m.submitted
@@ -284,13 +440,26 @@ func (m *InputModel) ClearSubmitted() {
 	m.submitted = false
 }
 
+// SetError shows an error found after the form was submitted.
+func (m *InputModel) SetError(err error) {
+	m.err = err
+}
+
 // GetConfig returns the transcription configuration.
 func (m *InputModel) GetConfig() *config.TranscriptionConfig {
 	cfg := &config.TranscriptionConfig{
-		Model:      m.model,
-		Timestamps: m.timestamps,
-		OutputDir:  m.outputDir,
+		Model:         m.model,
+		Timestamps:    m.timestamps,
+		OutputDir:     m.outputDir,
+		OutputFormats: m.formats,
+		Language:      m.language,
+		Task:          config.TaskTranscribe,
 	}
+	if m.translate {
+		cfg.Task = config.TaskTranslate
+	}
+	// Invalid times are reported on submit.
+	cfg.Start, cfg.End, _ = m.clipRange()
 	if m.sourceType == SourceURL {
 		cfg.URL = m.url
 	} else {
@@ -308,8 +477,10 @@ func (m *InputModel) Reset() {
 	m.err = nil
 	m.urlInput.SetValue("")
 	m.fileInput.SetValue("")
+	m.clipStart.SetValue("")
+	m.clipEnd.SetValue("")
 	m.urlInput.Focus()
-	m.focusIndex = 0
+	m.focusIndex = focusSource
 }
 
 // SetSize updates the screen dimensions.
@@ -321,6 +492,15 @@ func (m *InputModel) SetSize(w, h int) {
 	m.fileInput.Width = inputWidth
 }
 
+// step returns the option delta places from current, stopping at either end.
+func step(options []string, current string, delta int) string {
+	idx := indexOf(options, current) + delta
+	if idx < 0 || idx >= len(options) {
+		return current
+	}
+	return options[idx]
+}
+
 func indexOf(slice []string, item string) int {
 	for i, s := range slice {
 		if s == item {
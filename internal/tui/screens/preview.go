@@ -19,9 +19,10 @@ type PreviewModel struct {
 	viewport viewport.Model
 	renderer *glamour.TermRenderer
 
-	outputPath string
-	stats      pipeline.Stats
-	markdown   string
+	outputPath  string
+	outputPaths []string
+	stats       pipeline.Stats
+	markdown    string
 
 	focusedButton int
 	buttons       []string
@@ -56,8 +57,9 @@ func (m *PreviewModel) Init() tea.Cmd {
 }
 
 // SetResult sets the transcription result for display.
-func (m *PreviewModel) SetResult(outputPath string, stats pipeline.Stats) {
+func (m *PreviewModel) SetResult(outputPath string, outputPaths []string, stats pipeline.Stats) {
 	m.outputPath = outputPath
+	m.outputPaths = outputPaths
 	m.stats = stats
 
 	content, err := os.ReadFile(outputPath)
@@ -137,7 +139,7 @@ func (m *PreviewModel) View() string {
 
 	stats := fmt.Sprintf(
 		"Saved to: %s\nDuration: %s  •  Words: %d  •  Model: %s",
-		m.outputPath,
+		strings.Join(m.outputPaths, ", "),
 		m.stats.Duration,
 		m.stats.WordCount,
 		m.stats.Model,
@@ -201,6 +203,7 @@ func (m *PreviewModel) SetSize(w, h int) {
 // Reset resets the preview screen.
 func (m *PreviewModel) Reset() {
 	m.outputPath = ""
+	m.outputPaths = nil
 	m.markdown = ""
 	m.focusedButton = 0
 	m.startNew = false
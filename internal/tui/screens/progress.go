@@ -37,12 +37,14 @@ type ProgressModel struct {
 	progress progress.Model
 	viewport viewport.Model
 
-	title string
-	steps []PipelineStep
+	title   string
+	steps   []PipelineStep
+	barStep string
 
 	transcript strings.Builder
 
-	err error
+	err        error
+	cancelling bool
 
 	width  int
 	height int
@@ -54,22 +56,19 @@ func NewProgressModel(theme *styles.Theme) *ProgressModel {
 	s.Spinner = spinner.Dot
 	s.Style = theme.Spinner
 
-	p := progress.New(
-		progress.WithDefaultGradient(),
-		progress.WithWidth(50),
-	)
-
 	vp := viewport.New(60, 8)
 
 	return &ProgressModel{
 		theme:    theme,
 		spinner:  s,
-		progress: p,
+		progress: newProgressBar(0),
 		viewport: vp,
 		steps: []PipelineStep{
 			{Name: "Fetching video metadata", Key: "metadata", Status: StepPending},
 			{Name: "Downloading audio", Key: "download", Status: StepPending},
+			{Name: "Preparing audio", Key: "preprocess", Status: StepPending},
 			{Name: "Transcribing audio", Key: "transcribe", Status: StepPending},
+			{Name: "Applying glossary", Key: "glossary", Status: StepPending},
 			{Name: "Formatting Markdown", Key: "format", Status: StepPending},
 			{Name: "Validating output", Key: "validate", Status: StepPending},
 		},
@@ -101,6 +100,12 @@ func (m *ProgressModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case ProgressMsg:
 		m.updateStepStatus(msg.Step, msg.Progress, msg.Message)
+		if msg.Step != m.barStep {
+			// A new step starts its bar from zero rather than animating
+			// down from where the previous step ended.
+			m.barStep = msg.Step
+			m.progress = newProgressBar(m.width)
+		}
 		if msg.Progress > 0 && msg.Progress < 1 {
 			cmds = append(cmds, m.progress.SetPercent(msg.Progress))
 		}
@@ -168,6 +173,9 @@ func (m *ProgressModel) View() string {
 			status = m.theme.Dim.Render("pending")
 		case StepInProgress:
 			status = m.spinner.View()
+			if step.Progress > 0 && step.Progress < 1 {
+				status += m.theme.Dim.Render(fmt.Sprintf(" %3.0f%%", step.Progress*100))
+			}
 		case StepCompleted:
 			status = m.theme.Success.Render("done")
 		case StepError:
@@ -199,6 +207,16 @@ func (m *ProgressModel) View() string {
 		b.WriteString("\n")
 		previewBox := m.theme.Box.Width(m.width - 4).Render(m.viewport.View())
 		b.WriteString(previewBox)
+		b.WriteString("\n")
+	}
+
+	switch {
+	case m.cancelling:
+		b.WriteString("\n")
+		b.WriteString(m.theme.Help.Render("Cancelling..."))
+	case m.err == nil:
+		b.WriteString("\n")
+		b.WriteString(m.theme.Help.Render("esc cancel • ctrl+c quit"))
 	}
 
 	return b.String()
@@ -222,22 +240,44 @@ func (m *ProgressModel) SetError(err error) {
 	m.err = err
 }
 
+// SetCancelling shows that the job is being cancelled.
+func (m *ProgressModel) SetCancelling() {
+	m.cancelling = true
+}
+
+// Cancelling reports whether the job is being cancelled.
+func (m *ProgressModel) Cancelling() bool {
+	return m.cancelling
+}
+
 // SetSize updates the screen dimensions.
 func (m *ProgressModel) SetSize(w, h int) {
 	m.width = w
 	m.height = h
 	m.viewport.Width = max(40, w-10)
 	m.viewport.Height = 8
-	m.progress = progress.New(
+	m.progress = newProgressBar(w)
+}
+
+// newProgressBar creates a progress bar sized for the screen width, or a
+// default width before the first resize.
+func newProgressBar(w int) progress.Model {
+	width := 50
+	if w > 0 {
+		width = max(30, w-20)
+	}
+	return progress.New(
 		progress.WithDefaultGradient(),
-		progress.WithWidth(max(30, w-20)),
+		progress.WithWidth(width),
 	)
 }
 
 // Reset resets the progress screen for a new transcription.
 func (m *ProgressModel) Reset() {
 	m.title = ""
+	m.barStep = ""
 	m.err = nil
+	m.cancelling = false
 	m.transcript.Reset()
 	m.viewport.SetContent("")
 	for i := range m.steps {
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package workspace
+
+// processAlive reports every process as alive, since signal 0 isn't
+// supported here, so that cleanup never removes a running job's workspace.
+func processAlive(pid int) bool {
+	return true
+}
@@ -0,0 +1,208 @@
+// Package workspace gives each job a private temporary directory for
+// downloads and intermediate audio, and finds the ones left behind by jobs
+// that crashed or were killed.
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ownerFile holds the process ID of the job's process.
+const ownerFile = "owner"
+
+// legacyPrefixes name the temporary directories of earlier versions, which
+// created them directly in the system temp directory.
+var legacyPrefixes = []string{
+	"whisper-transcribe-out-",
+	"whisper-transcribe-audio-",
+	"whisper-transcribe-captions-",
+}
+
+// sharedDir is where earlier versions downloaded all audio.
+func sharedDir() string {
+	return filepath.Join(os.TempDir(), "whisper-transcribe")
+}
+
+// Root returns the directory holding the workspaces of this user's jobs.
+// Each user has their own, since one created by another user wouldn't be
+// writable.
+func Root() string {
+	name := "whisper-transcribe-jobs"
+	if uid := os.Getuid(); uid >= 0 {
+		name += "-" + strconv.Itoa(uid)
+	}
+	return filepath.Join(os.TempDir(), name)
+}
+
+// Workspace is a job's temporary directory.
+type Workspace struct {
+	Dir string
+}
+
+// New creates a workspace owned by this process.
+func New() (*Workspace, error) {
+	if err := os.MkdirAll(Root(), 0700); err != nil {
+		return nil, fmt.Errorf("create workspace: %w", err)
+	}
+	dir, err := os.MkdirTemp(Root(), "job-")
+	if err != nil {
+		return nil, fmt.Errorf("create workspace: %w", err)
+	}
+	pid := strconv.Itoa(os.Getpid())
+	if err := os.WriteFile(filepath.Join(dir, ownerFile), []byte(pid), 0644); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("create workspace: %w", err)
+	}
+	return &Workspace{Dir: dir}, nil
+}
+
+// Remove deletes the workspace and everything in it.
+func (w *Workspace) Remove() error {
+	return os.RemoveAll(w.Dir)
+}
+
+// Keep moves a file out of the workspace to dst, so that removing the
+// workspace doesn't delete it. It copies if dst is on another file system.
+func Keep(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("keep %s: %w", filepath.Base(src), err)
+	}
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("keep %s: %w", filepath.Base(src), err)
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("keep %s: %w", filepath.Base(src), err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return fmt.Errorf("keep %s: %w", filepath.Base(src), err)
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		return fmt.Errorf("keep %s: %w", filepath.Base(src), err)
+	}
+	return os.Remove(src)
+}
+
+// Stale is temporary data no running job uses.
+type Stale struct {
+	Path    string
+	Size    int64
+	ModTime time.Time
+}
+
+// FindStale returns the workspaces whose process has exited, and temporary
+// files and directories of earlier versions not modified within grace.
+// Workspaces without an owner are treated like the latter, since their
+// owner may still be writing it. Where processes can't be checked, owned
+// workspaces are never stale.
+func FindStale(grace time.Duration) ([]Stale, error) {
+	var stale []Stale
+	cutoff := time.Now().Add(-grace)
+
+	entries, err := os.ReadDir(Root())
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	for _, e := range entries {
+		path := filepath.Join(Root(), e.Name())
+		if !e.IsDir() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		pid, err := readOwner(path)
+		if err == nil && processAlive(pid) {
+			continue
+		}
+		if err != nil && info.ModTime().After(cutoff) {
+			continue
+		}
+		stale = append(stale, Stale{Path: path, Size: diskUsage(path), ModTime: info.ModTime()})
+	}
+
+	// Audio downloaded by earlier versions.
+	entries, err = os.ReadDir(sharedDir())
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		if s, ok := staleEntry(filepath.Join(sharedDir(), e.Name()), cutoff); ok {
+			stale = append(stale, s)
+		}
+	}
+
+	entries, err = os.ReadDir(os.TempDir())
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if !e.IsDir() || !hasLegacyPrefix(e.Name()) {
+			continue
+		}
+		if s, ok := staleEntry(filepath.Join(os.TempDir(), e.Name()), cutoff); ok {
+			stale = append(stale, s)
+		}
+	}
+	return stale, nil
+}
+
+func staleEntry(path string, cutoff time.Time) (Stale, bool) {
+	info, err := os.Stat(path)
+	if err != nil || info.ModTime().After(cutoff) {
+		return Stale{}, false
+	}
+	return Stale{Path: path, Size: diskUsage(path), ModTime: info.ModTime()}, true
+}
+
+func hasLegacyPrefix(name string) bool {
+	for _, prefix := range legacyPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func readOwner(dir string) (int, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ownerFile))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
+
+// diskUsage returns the total size of the files under path.
+func diskUsage(path string) int64 {
+	var size int64
+	filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
@@ -0,0 +1,24 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestKeep(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "audio.wav")
+	dst := filepath.Join(dir, "out", "talk.wav")
+	os.WriteFile(src, []byte("RIFF"), 0644)
+
+	if err := Keep(src, dst); err != nil {
+		t.Fatalf("Keep: %v", err)
+	}
+	if data, err := os.ReadFile(dst); err != nil || string(data) != "RIFF" {
+		t.Errorf("kept file = %q, %v", data, err)
+	}
+	if _, err := os.Stat(src); !os.IsNotExist(err) {
+		t.Error("expected source to be moved")
+	}
+}
@@ -0,0 +1,62 @@
+//go:build unix
+
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// FindStale needs to tell that a workspace's process has exited, which it
+// can only do on Unix.
+func TestFindStale(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+
+	live, err := New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(live.Dir, "audio.wav"), make([]byte, 100), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A workspace whose process is gone.
+	dead, err := New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	os.WriteFile(filepath.Join(dead.Dir, ownerFile), []byte("99999999"), 0644)
+	os.WriteFile(filepath.Join(dead.Dir, "audio.wav"), make([]byte, 1000), 0644)
+
+	// Audio from an earlier version, one old and one possibly in use.
+	os.MkdirAll(sharedDir(), 0755)
+	old := filepath.Join(sharedDir(), "abc.wav")
+	recent := filepath.Join(sharedDir(), "def.wav")
+	os.WriteFile(old, make([]byte, 10), 0644)
+	os.WriteFile(recent, make([]byte, 10), 0644)
+	past := time.Now().Add(-2 * time.Hour)
+	os.Chtimes(old, past, past)
+
+	stale, err := FindStale(time.Hour)
+	if err != nil {
+		t.Fatalf("FindStale: %v", err)
+	}
+	if len(stale) != 2 {
+		t.Fatalf("expected 2 stale entries, got %+v", stale)
+	}
+	if stale[0].Path != dead.Dir || stale[0].Size != 1000+8 {
+		t.Errorf("unexpected workspace entry: %+v", stale[0])
+	}
+	if stale[1].Path != old {
+		t.Errorf("unexpected legacy entry: %+v", stale[1])
+	}
+
+	if err := live.Remove(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(live.Dir); !os.IsNotExist(err) {
+		t.Errorf("workspace not removed: %v", err)
+	}
+}